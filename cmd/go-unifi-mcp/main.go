@@ -7,7 +7,10 @@ import (
 	"io"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/claytono/go-unifi-mcp/internal/audit"
 	"github.com/claytono/go-unifi-mcp/internal/config"
 	"github.com/claytono/go-unifi-mcp/internal/server"
 	"github.com/filipowm/go-unifi/unifi"
@@ -22,17 +25,52 @@ func main() {
 
 type runner struct {
 	loadConfig func() (*config.Config, error)
+	loadFile   func(path, profile string) (*config.Config, error)
 	newClient  func(*config.Config) (unifi.Client, error)
 	newServer  func(server.Options) (*mcpserver.MCPServer, error)
-	serve      func(*mcpserver.MCPServer) error
+	serve      func(*mcpserver.MCPServer, *config.Config) error
+	// watchReload reloads the configuration's API keys on request until the
+	// returned function is called.
+	watchReload func(*config.Config) (stop func())
 }
 
 func defaultRunner() runner {
 	return runner{
 		loadConfig: config.Load,
+		loadFile:   config.LoadFile,
 		newClient:  server.NewClient,
 		newServer:  server.New,
 		serve:      server.Serve,
+		watchReload: func(cfg *config.Config) func() {
+			return reloadSecretsOnSignal(cfg, log.Default(), syscall.SIGHUP)
+		},
+	}
+}
+
+// reloadSecretsOnSignal re-reads API keys from their file or the credential
+// command whenever one of the signals is received, until the returned function is called.
+// Failures are logged and leave the previous credentials in place.
+func reloadSecretsOnSignal(cfg *config.Config, logger *log.Logger, signals ...os.Signal) func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := cfg.ReloadSecrets(); err != nil {
+					logger.Printf("Error reloading credentials: %v", err)
+				} else {
+					logger.Printf("Credentials reloaded")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
 	}
 }
 
@@ -42,24 +80,101 @@ func printUsage(w io.Writer) {
 Usage: go-unifi-mcp [flags]
 
 Flags:
+  -config       YAML config file (overrides UNIFI_CONFIG, default: %s)
+  -profile      Config file profile to use (overrides UNIFI_PROFILE)
+  -transport    Transport to serve on: stdio|http (overrides UNIFI_TRANSPORT)
+  -http-addr    HTTP listen address (overrides UNIFI_HTTP_ADDR)
   -version      Print version and exit
   -help, -h     Show this help message
 
 Environment variables:
+  UNIFI_CONFIG      YAML config file with controller profiles
+  UNIFI_PROFILE     Config file profile to use (default: the file's
+                    default_profile)
+  UNIFI_CONTROLLERS Comma-separated config file profiles to serve alongside
+                    the selected one (default: the file's controllers)
   UNIFI_HOST        UniFi controller URL (required)
   UNIFI_API_KEY     API key (preferred auth method)
+  UNIFI_API_KEY_FILE
+                    File holding the API key, e.g. a Docker secret
   UNIFI_USERNAME    Username for password auth
   UNIFI_PASSWORD    Password for password auth
-  UNIFI_SITE        UniFi site name (default: "default")
+  UNIFI_PASSWORD_FILE
+                    File holding the password
+  UNIFI_CREDENTIAL_COMMAND
+                    Command printing the API key, or the password if
+                    UNIFI_USERNAME is set
+  UNIFI_SITE        UniFi site name or description (default: "default")
+  UNIFI_ALLOWED_SITES
+                    Comma-separated sites tools may access (default: all)
   UNIFI_VERIFY_SSL  Verify SSL certificates (default: true)
+  UNIFI_CA_CERT     PEM file of CA certificates to trust instead of the
+                    system roots
+  UNIFI_CERT_FINGERPRINT
+                    SHA-256 fingerprint the controller's certificate must
+                    match; allows self-signed certificates
+  UNIFI_TIMEOUT     Timeout for connecting to the controller and awaiting
+                    each response, 0 for none (default: 30s)
+  UNIFI_PROXY       HTTP(S) proxy for controller requests (default:
+                    HTTPS_PROXY/HTTP_PROXY/NO_PROXY)
+  UNIFI_RETRIES     Retries of idempotent requests on 5xx, 429 and
+                    connection resets (default: 2)
+  UNIFI_RETRY_BACKOFF
+                    Delay before the first retry, doubled for each further
+                    one (default: 500ms)
+  UNIFI_TOOL_TIMEOUT
+                    Deadline for each tool call, 0 for none (default: 2m)
+  UNIFI_BATCH_CONCURRENCY
+                    Calls of a batch run at once (default: 8)
+  UNIFI_RATE_LIMIT  Tool calls per second per controller, 0 for no limit
+                    (default: 0)
+  UNIFI_RATE_BURST  Tool calls allowed in a burst before UNIFI_RATE_LIMIT
+                    applies (default: 10)
+  UNIFI_CACHE_TTL   How long list/get results are cached, 0 to disable
+                    (default: 0)
+  UNIFI_CACHE_TTLS  Per-resource cache TTLs, e.g. "Device=5s,Network=5m"
+  UNIFI_OUTPUT_FORMAT
+                    Format of tool results: json|json-indent|yaml|csv|markdown
+                    (default: "json-indent")
   UNIFI_TOOL_MODE   Tool registration mode: lazy|eager (default: "lazy")
-`)
+  UNIFI_READ_ONLY   Only expose list/get tools (default: false)
+  UNIFI_DRY_RUN     Preview create/update/delete calls without applying them
+  UNIFI_CONFIRM_TOOLS
+                    Tool patterns that need user confirmation, or "none"
+                    (default: deletes, update_setting_mgmt, update_network,
+                    firewall changes)
+  UNIFI_REVEAL_SECRETS
+                    Show passphrases, RADIUS secrets and other credentials in
+                    tool results instead of masking them (default: false)
+  UNIFI_AUDIT_LOG   JSONL file recording every create/update/delete
+  UNIFI_AUDIT_LOG_MAX_MB
+                    Rotate the audit log at this size, 0 to never (default: 100)
+  UNIFI_UNDO_HISTORY
+                    Number of changes kept for list_changes and undo,
+                    0 to disable (default: 0)
+  UNIFI_TOOLS_INCLUDE
+                    Comma-separated tool patterns to expose (default: all)
+  UNIFI_TOOLS_EXCLUDE
+                    Comma-separated tool patterns to hide
+  UNIFI_TRANSPORT   Transport to serve on: stdio|http (default: "stdio")
+  UNIFI_HTTP_ADDR   HTTP listen address (default: "127.0.0.1:8080"); other
+                    than loopback addresses need UNIFI_HTTP_TOKEN_FILE
+  UNIFI_HTTP_TOKEN_FILE
+                    YAML file of bearer tokens and roles for the HTTP transport
+
+Environment variables override values from the config file profile.
+Send SIGHUP to re-read an API key from its file or the credential command.
+`, config.DefaultPath())
 }
 
 func mainWith(r runner, exit func(int), logger *log.Logger, args []string, output io.Writer) {
 	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
 	fs.SetOutput(output)
 	versionFlag := fs.Bool("version", false, "Print version and exit")
+	configFlag := fs.String("config", "", "YAML config file")
+	profileFlag := fs.String("profile", "", "Config file profile to use")
+	transportFlag := fs.String("transport", "", "Transport to serve on: stdio|http")
+	httpAddrFlag := fs.String("http-addr", "", "HTTP listen address")
 
 	fs.Usage = func() { printUsage(output) }
 
@@ -79,6 +194,12 @@ func mainWith(r runner, exit func(int), logger *log.Logger, args []string, outpu
 		return
 	}
 
+	if *configFlag != "" || *profileFlag != "" {
+		loadFile, path, profile := r.loadFile, *configFlag, *profileFlag
+		r.loadConfig = func() (*config.Config, error) { return loadFile(path, profile) }
+	}
+	r.loadConfig = withFlagOverrides(r.loadConfig, *transportFlag, *httpAddrFlag)
+
 	if err := runWith(r); err != nil {
 		var cfgErr *configError
 		if errors.As(err, &cfgErr) {
@@ -90,6 +211,24 @@ func mainWith(r runner, exit func(int), logger *log.Logger, args []string, outpu
 	}
 }
 
+// withFlagOverrides applies command-line flags on top of the loaded configuration.
+// Empty flag values leave the environment-derived settings untouched.
+func withFlagOverrides(load func() (*config.Config, error), transport, httpAddr string) func() (*config.Config, error) {
+	return func() (*config.Config, error) {
+		cfg, err := load()
+		if err != nil {
+			return nil, err
+		}
+		if transport != "" {
+			cfg.Transport = transport
+		}
+		if httpAddr != "" {
+			cfg.HTTPAddr = httpAddr
+		}
+		return cfg, nil
+	}
+}
+
 type configError struct {
 	err error
 }
@@ -110,14 +249,62 @@ func runWith(r runner) error {
 		return err
 	}
 
+	// Create a client for every additional controller
+	var controllers []server.Controller
+	for _, ctrlCfg := range cfg.Controllers {
+		ctrlClient, err := r.newClient(ctrlCfg)
+		if err != nil {
+			return fmt.Errorf("controller %s: %w", ctrlCfg.Profile, err)
+		}
+		controllers = append(controllers, server.Controller{
+			Name:         ctrlCfg.Profile,
+			Client:       ctrlClient,
+			Site:         ctrlCfg.Site,
+			AllowedSites: ctrlCfg.AllowedSites,
+		})
+	}
+
+	// Re-read API keys from files and the credential command on request
+	defer r.watchReload(cfg)()
+
+	// Open the audit log, if configured
+	var auditLog *audit.Logger
+	if cfg.AuditLog != "" {
+		auditLog, err = audit.Open(cfg.AuditLog, cfg.AuditLogMaxBytes)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = auditLog.Close() }()
+	}
+
 	// Create MCP server
 	s, err := r.newServer(server.Options{
-		Client: client,
+		Client:        client,
+		Controller:    cfg.Profile,
+		Controllers:   controllers,
+		Mode:          server.Mode(cfg.ToolMode),
+		Site:          cfg.Site,
+		AllowedSites:  cfg.AllowedSites,
+		ReadOnly:      cfg.ReadOnly,
+		DryRun:        cfg.DryRun,
+		ConfirmTools:  cfg.ConfirmTools,
+		RevealSecrets: cfg.RevealSecrets,
+		AuditLog:      auditLog,
+		UndoHistory:   cfg.UndoHistory,
+		ToolTimeout:   cfg.ToolTimeout,
+		BatchWorkers:  cfg.BatchConcurrency,
+		RateLimit:     cfg.RateLimit,
+		RateBurst:     cfg.RateBurst,
+		CacheTTL:      cfg.CacheTTL,
+		CacheTTLs:     cfg.CacheTTLs,
+		OutputFormat:  cfg.OutputFormat,
+		ToolsInclude:  cfg.ToolsInclude,
+		ToolsExclude:  cfg.ToolsExclude,
 	})
 	if err != nil {
 		return err
 	}
 
-	// Start serving
-	return r.serve(s)
+	// Start serving on the configured transport
+	return r.serve(s, cfg)
 }
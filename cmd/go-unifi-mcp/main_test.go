@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"errors"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/claytono/go-unifi-mcp/internal/config"
 	"github.com/claytono/go-unifi-mcp/internal/server"
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
 	"github.com/filipowm/go-unifi/unifi"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
@@ -50,7 +57,7 @@ func TestRunNewServerError(t *testing.T) {
 func TestRunServeError(t *testing.T) {
 	expectedErr := errors.New("serve")
 	r := baseRunner()
-	r.serve = func(s *mcpserver.MCPServer) error {
+	r.serve = func(s *mcpserver.MCPServer, cfg *config.Config) error {
 		return expectedErr
 	}
 
@@ -61,7 +68,7 @@ func TestRunServeError(t *testing.T) {
 func TestRunSuccess(t *testing.T) {
 	r := baseRunner()
 	called := false
-	r.serve = func(s *mcpserver.MCPServer) error {
+	r.serve = func(s *mcpserver.MCPServer, cfg *config.Config) error {
 		called = true
 		return nil
 	}
@@ -153,7 +160,7 @@ func TestUnknownFlagExitsWithCode2(t *testing.T) {
 
 func TestMainNoUsageOnRuntimeError(t *testing.T) {
 	r := baseRunner()
-	r.serve = func(s *mcpserver.MCPServer) error {
+	r.serve = func(s *mcpserver.MCPServer, cfg *config.Config) error {
 		return errors.New("connection lost")
 	}
 	buf := &bytes.Buffer{}
@@ -192,11 +199,141 @@ func TestMainExitsOnError(t *testing.T) {
 func TestDefaultRunner(t *testing.T) {
 	r := defaultRunner()
 	require.NotNil(t, r.loadConfig)
+	require.NotNil(t, r.loadFile)
 	require.NotNil(t, r.newClient)
 	require.NotNil(t, r.newServer)
 	require.NotNil(t, r.serve)
 }
 
+func TestFlagsOverrideTransport(t *testing.T) {
+	r := baseRunner()
+	var served *config.Config
+	r.serve = func(s *mcpserver.MCPServer, cfg *config.Config) error {
+		served = cfg
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+	exitCode := -1
+	exitFn := func(code int) { exitCode = code }
+
+	mainWith(r, exitFn, logger, []string{"go-unifi-mcp", "-transport", "http", "-http-addr", "127.0.0.1:9000"}, buf)
+	require.Equal(t, -1, exitCode)
+	require.NotNil(t, served)
+	assert.Equal(t, config.TransportHTTP, served.Transport)
+	assert.Equal(t, "127.0.0.1:9000", served.HTTPAddr)
+}
+
+func TestFlagsSelectConfigProfile(t *testing.T) {
+	r := baseRunner()
+	var gotPath, gotProfile string
+	r.loadFile = func(path, profile string) (*config.Config, error) {
+		gotPath, gotProfile = path, profile
+		return &config.Config{Profile: profile, Transport: config.TransportStdio}, nil
+	}
+	var served *config.Config
+	r.serve = func(s *mcpserver.MCPServer, cfg *config.Config) error {
+		served = cfg
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+
+	mainWith(r, func(int) {}, logger, []string{"go-unifi-mcp", "-config", "/etc/unifi.yaml", "-profile", "lab", "-transport", "http"}, buf)
+	assert.Equal(t, "/etc/unifi.yaml", gotPath)
+	assert.Equal(t, "lab", gotProfile)
+	require.NotNil(t, served)
+	assert.Equal(t, "lab", served.Profile)
+	assert.Equal(t, config.TransportHTTP, served.Transport)
+}
+
+func TestFlagsKeepConfigWhenUnset(t *testing.T) {
+	r := baseRunner()
+	r.loadConfig = func() (*config.Config, error) {
+		return &config.Config{Transport: config.TransportStdio, HTTPAddr: ":8080"}, nil
+	}
+	var served *config.Config
+	r.serve = func(s *mcpserver.MCPServer, cfg *config.Config) error {
+		served = cfg
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+
+	mainWith(r, func(int) {}, logger, []string{"go-unifi-mcp"}, buf)
+	require.NotNil(t, served)
+	assert.Equal(t, config.TransportStdio, served.Transport)
+	assert.Equal(t, ":8080", served.HTTPAddr)
+}
+
+func TestRunPassesServerOptions(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	r := baseRunner()
+	r.loadConfig = func() (*config.Config, error) {
+		return &config.Config{
+			AuditLog:         auditPath,
+			UndoHistory:      25,
+			ToolTimeout:      time.Minute,
+			BatchConcurrency: 4,
+			RateLimit:        2.5,
+			RateBurst:        5,
+			CacheTTL:         time.Minute,
+			CacheTTLs:        map[string]time.Duration{"Device": 5 * time.Second},
+			OutputFormat:     "yaml",
+			ToolMode:         config.ToolModeEager,
+			Site:             "Branch Office",
+			AllowedSites:     []string{"Branch Office"},
+			ReadOnly:         true,
+			DryRun:           true,
+			RevealSecrets:    true,
+			ConfirmTools:     []string{"category:delete"},
+			ToolsInclude:     []string{"resource:Network"},
+			ToolsExclude:     []string{"category:delete"},
+		}, nil
+	}
+	var captured server.Options
+	r.newServer = func(opts server.Options) (*mcpserver.MCPServer, error) {
+		captured = opts
+		return nil, nil
+	}
+
+	require.NoError(t, runWith(r))
+	assert.Equal(t, "Branch Office", captured.Site)
+	assert.Equal(t, []string{"Branch Office"}, captured.AllowedSites)
+	assert.True(t, captured.ReadOnly)
+	assert.True(t, captured.DryRun)
+	assert.True(t, captured.RevealSecrets)
+	assert.Equal(t, []string{"category:delete"}, captured.ConfirmTools)
+	assert.NotNil(t, captured.AuditLog)
+	assert.FileExists(t, auditPath)
+	assert.Equal(t, 25, captured.UndoHistory)
+	assert.Equal(t, time.Minute, captured.ToolTimeout)
+	assert.Equal(t, 4, captured.BatchWorkers)
+	assert.Equal(t, 2.5, captured.RateLimit)
+	assert.Equal(t, 5, captured.RateBurst)
+	assert.Equal(t, time.Minute, captured.CacheTTL)
+	assert.Equal(t, map[string]time.Duration{"Device": 5 * time.Second}, captured.CacheTTLs)
+	assert.Equal(t, "yaml", captured.OutputFormat)
+	assert.Equal(t, server.ModeEager, captured.Mode)
+	assert.Equal(t, []string{"resource:Network"}, captured.ToolsInclude)
+	assert.Equal(t, []string{"category:delete"}, captured.ToolsExclude)
+}
+
+func TestRunAuditLogOpenError(t *testing.T) {
+	r := baseRunner()
+	r.loadConfig = func() (*config.Config, error) {
+		return &config.Config{AuditLog: filepath.Join(t.TempDir(), "missing", "audit.jsonl")}, nil
+	}
+	r.newServer = func(opts server.Options) (*mcpserver.MCPServer, error) {
+		t.Fatal("server must not be created without its audit log")
+		return nil, nil
+	}
+
+	err := runWith(r)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open audit log")
+}
+
 func baseRunner() runner {
 	return runner{
 		loadConfig: func() (*config.Config, error) {
@@ -208,8 +345,99 @@ func baseRunner() runner {
 		newServer: func(opts server.Options) (*mcpserver.MCPServer, error) {
 			return nil, nil
 		},
-		serve: func(s *mcpserver.MCPServer) error {
+		serve: func(s *mcpserver.MCPServer, cfg *config.Config) error {
 			return nil
 		},
+		watchReload: func(*config.Config) func() {
+			return func() {}
+		},
+	}
+}
+
+func TestRunCreatesControllerClients(t *testing.T) {
+	r := baseRunner()
+	r.loadConfig = func() (*config.Config, error) {
+		return &config.Config{
+			Profile: "home",
+			Host:    "https://192.168.1.1",
+			Controllers: []*config.Config{
+				{Profile: "lab", Host: "https://10.0.0.1", Site: "Lab", AllowedSites: []string{"Lab"}},
+			},
+		}, nil
+	}
+	clients := map[string]unifi.Client{}
+	r.newClient = func(cfg *config.Config) (unifi.Client, error) {
+		client := servermocks.NewClient(t)
+		clients[cfg.Host] = client
+		return client, nil
+	}
+	var captured server.Options
+	r.newServer = func(opts server.Options) (*mcpserver.MCPServer, error) {
+		captured = opts
+		return nil, nil
+	}
+
+	require.NoError(t, runWith(r))
+	assert.Equal(t, "home", captured.Controller)
+	assert.Same(t, clients["https://192.168.1.1"], captured.Client)
+	require.Len(t, captured.Controllers, 1)
+	assert.Equal(t, "lab", captured.Controllers[0].Name)
+	assert.Same(t, clients["https://10.0.0.1"], captured.Controllers[0].Client)
+	assert.Equal(t, "Lab", captured.Controllers[0].Site)
+	assert.Equal(t, []string{"Lab"}, captured.Controllers[0].AllowedSites)
+}
+
+func TestRunControllerClientError(t *testing.T) {
+	r := baseRunner()
+	r.loadConfig = func() (*config.Config, error) {
+		return &config.Config{Controllers: []*config.Config{{Profile: "lab", Host: "https://10.0.0.1"}}}, nil
 	}
+	r.newClient = func(cfg *config.Config) (unifi.Client, error) {
+		if cfg.Profile == "lab" {
+			return nil, errors.New("connection refused")
+		}
+		return nil, nil
+	}
+
+	err := runWith(r)
+	require.Error(t, err)
+	assert.EqualError(t, err, "controller lab: connection refused")
+}
+
+func TestReloadSecretsOnSignal(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("first-key"), 0o600))
+	cfg := &config.Config{APIKeyFile: keyFile}
+	require.NoError(t, cfg.ReadSecrets())
+
+	buf := &syncBuffer{}
+	stop := reloadSecretsOnSignal(cfg, log.New(buf, "", 0), syscall.SIGUSR1)
+	defer stop()
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("second-key"), 0o600))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, func() bool {
+		return cfg.APIKeySecret.Value() == "second-key"
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "Credentials reloaded")
+	}, time.Second, 10*time.Millisecond)
+}
+
+// syncBuffer is a bytes.Buffer safe for use by a logger in another goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
 }
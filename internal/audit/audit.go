@@ -0,0 +1,260 @@
+// Package audit keeps a persistent trail of every change made through the
+// server. Each create, update and delete call is appended to a JSONL file
+// with the state of the resource before and after the call.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/auth"
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	"github.com/claytono/go-unifi-mcp/internal/redact"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Outcomes recorded for a call.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Redacted replaces the value of secret fields in audit records. It is the
+// marker of masked tool results, so both read the same.
+const Redacted = redact.Marker
+
+// Record is one line of the audit log.
+type Record struct {
+	Time       time.Time      `json:"time"`
+	Session    string         `json:"session,omitempty"`
+	Identity   string         `json:"identity,omitempty"` // token name on the HTTP transport
+	Tool       string         `json:"tool"`
+	Controller string         `json:"controller,omitempty"` // empty with a single controller
+	Site       string         `json:"site"`
+	Arguments  map[string]any `json:"arguments"`
+	Before     any            `json:"before"` // nil for creates
+	After      any            `json:"after"`  // nil for deletes and failed calls
+	Outcome    string         `json:"outcome"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Logger appends records to a JSONL file. When the file would grow beyond
+// maxBytes it is renamed with a timestamp suffix and a new file is started;
+// rotated files are never deleted.
+type Logger struct {
+	path     string
+	maxBytes int64
+	now      func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open opens (or creates) the audit log at path. A maxBytes of zero disables
+// rotation.
+func Open(path string, maxBytes int64) (*Logger, error) {
+	l := &Logger{path: path, maxBytes: maxBytes, now: time.Now}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// Write appends a record to the log, rotating the file first if needed.
+func (l *Logger) Write(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log %s is closed", l.path)
+	}
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	l.file = nil
+	rotated := l.path + "." + l.now().UTC().Format("20060102T150405.000000000Z")
+	if err := os.Rename(l.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return l.open()
+}
+
+// Close closes the log file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Middleware records every call of a mutating tool. Dry runs are not
+// recorded since they change nothing. A record that cannot be written is
+// reported on the server log; the call's result is returned unchanged.
+func (l *Logger) Middleware(client unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if registry.IsReadOnly(meta) {
+		return next
+	}
+	secrets := redact.SecretsFor(meta.Resource)
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		if dryRun, _ := args[generated.DryRunArg].(bool); dryRun {
+			return next(ctx, req)
+		}
+
+		site, _ := args["site"].(string)
+		id, _ := args["id"].(string)
+		rec := Record{
+			Tool:       meta.Name,
+			Controller: controllers.NameFromContext(ctx),
+			Site:       site,
+			Arguments:  Redact(args, secrets).(map[string]any),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			rec.Session = session.SessionID()
+		}
+		if identity, ok := auth.IdentityFromContext(ctx); ok {
+			rec.Identity = identity.Name
+		}
+		if meta.Category != "create" {
+			// A failed lookup leaves before empty; the call itself reports
+			// the problem if the resource is really missing. The snapshot is
+			// passed on so the journal and the update itself use it too.
+			var before any
+			var err error
+			if ctx, before, err = generated.Snapshot(ctx, client, meta.Resource, site, id); err == nil {
+				rec.Before = redactObject(before, secrets)
+			}
+		}
+
+		result, err := next(ctx, req)
+
+		rec.Time = l.now().UTC()
+		switch {
+		case err != nil:
+			rec.Outcome = OutcomeError
+			rec.Error = err.Error()
+		case result == nil:
+			rec.Outcome = OutcomeError
+			rec.Error = "no result"
+		case result.IsError:
+			rec.Outcome = OutcomeError
+			rec.Error = resultText(result)
+		default:
+			rec.Outcome = OutcomeSuccess
+			if meta.Category != "delete" {
+				var after any
+				if json.Unmarshal([]byte(resultText(result)), &after) == nil {
+					rec.After = Redact(after, secrets)
+				}
+			}
+		}
+
+		if werr := l.Write(rec); werr != nil {
+			log.Printf("audit: %s: %v", meta.Name, werr)
+		}
+		return result, err
+	}
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// redactObject converts a typed resource to its JSON form and redacts it.
+func redactObject(v any, secrets map[string]bool) any {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil
+	}
+	return Redact(decoded, secrets)
+}
+
+// Redact returns a copy of v with the values of the given secret fields,
+// the generated secret fields of a resource, replaced by Redacted,
+// descending into nested objects and arrays.
+func Redact(v any, secrets map[string]bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			if secrets[k] {
+				out[k] = Redacted
+				continue
+			}
+			out[k] = Redact(val, secrets)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = Redact(val, secrets)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,212 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/auth"
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	updateWLAN = generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"}
+	createWLAN = generated.ToolMetadata{Name: "create_wlan", Category: "create", Resource: "WLAN"}
+	deleteWLAN = generated.ToolMetadata{Name: "delete_wlan", Category: "delete", Resource: "WLAN"}
+)
+
+func openTestLogger(t *testing.T, maxBytes int64) (*Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, maxBytes)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	return l, path
+}
+
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		records = append(records, rec)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func call(t *testing.T, ctx context.Context, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handler(ctx, req)
+	require.NoError(t, err)
+	return result
+}
+
+func TestOpen_Error(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing", "audit.jsonl"), 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open audit log")
+}
+
+func TestLogger_AppendsToExistingFile(t *testing.T) {
+	l, path := openTestLogger(t, 0)
+	require.NoError(t, l.Write(Record{Tool: "first"}))
+	require.NoError(t, l.Close())
+
+	l, err := Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, l.Write(Record{Tool: "second"}))
+	require.NoError(t, l.Close())
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+	assert.Equal(t, "first", records[0].Tool)
+	assert.Equal(t, "second", records[1].Tool)
+
+	assert.Error(t, l.Write(Record{Tool: "closed"}))
+}
+
+func TestLogger_Rotation(t *testing.T) {
+	line, err := json.Marshal(Record{Tool: "tool"})
+	require.NoError(t, err)
+	// Room for two records per file.
+	l, path := openTestLogger(t, int64(2*(len(line)+1)))
+	l.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Write(Record{Tool: "tool"}))
+	}
+
+	rotated := path + ".20261016T120000.000000000Z"
+	assert.Len(t, readRecords(t, rotated), 2)
+	assert.Len(t, readRecords(t, path), 1)
+}
+
+func TestRedact(t *testing.T) {
+	secrets := map[string]bool{"x_passphrase": true, "x_secret": true, "password": true}
+	in := map[string]any{
+		"name":         "Home",
+		"x_passphrase": "hunter22",
+		"x_mesh_essid": "mesh",
+		"radius": map[string]any{
+			"x_secret": "s3cret",
+			"port":     float64(1812),
+		},
+		"users": []any{map[string]any{"name": "a", "password": "pw"}},
+	}
+
+	// Only the given fields are secret, whatever the others are called.
+	assert.Equal(t, map[string]any{
+		"name":         "Home",
+		"x_passphrase": Redacted,
+		"x_mesh_essid": "mesh",
+		"radius": map[string]any{
+			"x_secret": Redacted,
+			"port":     float64(1812),
+		},
+		"users": []any{map[string]any{"name": "a", "password": Redacted}},
+	}, Redact(in, secrets))
+	// The input is left alone.
+	assert.Equal(t, "hunter22", in["x_passphrase"])
+}
+
+func TestMiddleware_RecordsUpdate(t *testing.T) {
+	l, path := openTestLogger(t, 0)
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").
+		Return(&unifi.WLAN{ID: "w1", Name: "Home", XPassphrase: "old-passphrase"}, nil).Once()
+
+	next := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"_id": "w1", "name": "Home 2", "x_passphrase": "new-passphrase"}`), nil
+	}
+
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Name: "ci", Role: auth.RoleAdmin})
+	ctx = controllers.WithName(ctx, "lab")
+	result := call(t, ctx, l.Middleware(client, updateWLAN, next), map[string]any{
+		"site": "default", "id": "w1", "name": "Home 2", "x_passphrase": "new-passphrase", "x_mesh_essid": "mesh",
+	})
+	assert.False(t, result.IsError)
+
+	records := readRecords(t, path)
+	require.Len(t, records, 1)
+	rec := records[0]
+	assert.Equal(t, "update_wlan", rec.Tool)
+	assert.Equal(t, "default", rec.Site)
+	assert.Equal(t, "ci", rec.Identity)
+	assert.Equal(t, "lab", rec.Controller)
+	assert.Equal(t, OutcomeSuccess, rec.Outcome)
+	assert.False(t, rec.Time.IsZero())
+	// Secrets are the generated secret fields of the resource, so other x_
+	// fields are kept.
+	assert.Equal(t, map[string]any{
+		"site": "default", "id": "w1", "name": "Home 2", "x_passphrase": Redacted, "x_mesh_essid": "mesh",
+	}, rec.Arguments)
+	assert.Equal(t, "Home", rec.Before.(map[string]any)["name"])
+	assert.Equal(t, Redacted, rec.Before.(map[string]any)["x_passphrase"])
+	assert.Equal(t, map[string]any{"_id": "w1", "name": "Home 2", "x_passphrase": Redacted}, rec.After)
+}
+
+func TestMiddleware_RecordsCreateAndFailedDelete(t *testing.T) {
+	l, path := openTestLogger(t, 0)
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "gone").Return(nil, errors.New("not found")).Once()
+
+	created := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"_id": "w2", "name": "Guest"}`), nil
+	}
+	failed := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("not found"), nil
+	}
+
+	call(t, context.Background(), l.Middleware(client, createWLAN, created), map[string]any{"site": "default", "name": "Guest"})
+	call(t, context.Background(), l.Middleware(client, deleteWLAN, failed), map[string]any{"site": "default", "id": "gone"})
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "create_wlan", records[0].Tool)
+	assert.Nil(t, records[0].Before)
+	assert.Equal(t, map[string]any{"_id": "w2", "name": "Guest"}, records[0].After)
+	assert.Equal(t, OutcomeSuccess, records[0].Outcome)
+
+	assert.Equal(t, "delete_wlan", records[1].Tool)
+	assert.Nil(t, records[1].Before)
+	assert.Nil(t, records[1].After)
+	assert.Equal(t, OutcomeError, records[1].Outcome)
+	assert.Equal(t, "not found", records[1].Error)
+}
+
+func TestMiddleware_SkipsReadsAndDryRuns(t *testing.T) {
+	l, path := openTestLogger(t, 0)
+	client := servermocks.NewClient(t)
+	next := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{}`), nil
+	}
+
+	list := generated.ToolMetadata{Name: "list_wlan", Category: "list", Resource: "WLAN"}
+	call(t, context.Background(), l.Middleware(client, list, next), map[string]any{"site": "default"})
+	call(t, context.Background(), l.Middleware(client, deleteWLAN, next), map[string]any{"site": "default", "id": "w1", "dry_run": true})
+
+	assert.Empty(t, readRecords(t, path))
+}
@@ -0,0 +1,231 @@
+// Package auth provides bearer-token authentication and role-based
+// authorization for the HTTP transport.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// Role grants a fixed set of permissions over UniFi tools.
+type Role string
+
+const (
+	// RoleViewer may list and get resources.
+	RoleViewer Role = "viewer"
+	// RoleOperator may also create and update resources, but not settings or
+	// the resources in adminResources.
+	RoleOperator Role = "operator"
+	// RoleAdmin may call every tool, including deletes and settings updates.
+	RoleAdmin Role = "admin"
+)
+
+// PermissionRevealSecrets lets a token see the credentials, such as
+// passphrases and RADIUS secrets, that are otherwise masked in tool results.
+// Roles never include it; it must be granted to each token.
+const PermissionRevealSecrets = "reveal_secrets"
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	Name        string
+	Role        Role
+	Permissions []string // granted in addition to the role's
+}
+
+// Token binds an API token to a named identity and role.
+type Token struct {
+	Name        string   `yaml:"name"`
+	Token       string   `yaml:"token"`
+	Role        Role     `yaml:"role"`
+	Permissions []string `yaml:"permissions"` // e.g. reveal_secrets
+}
+
+// TokenFile is the on-disk format of the token file.
+type TokenFile struct {
+	Tokens []Token `yaml:"tokens"`
+}
+
+// TokenStore resolves bearer tokens to identities.
+type TokenStore struct {
+	tokens []Token
+}
+
+// LoadTokenFile reads and validates a YAML token file.
+func LoadTokenFile(path string) (*TokenStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var file TokenFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse token file: %w", err)
+	}
+
+	return NewTokenStore(file.Tokens)
+}
+
+// NewTokenStore validates tokens and returns a store for them.
+func NewTokenStore(tokens []Token) (*TokenStore, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("token file defines no tokens")
+	}
+
+	seen := make(map[string]struct{}, len(tokens))
+	for i, t := range tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("token %d (%s) is empty", i, t.Name)
+		}
+		if !t.Role.valid() {
+			return nil, fmt.Errorf("token %d (%s) has unknown role %q (expected viewer, operator or admin)", i, t.Name, t.Role)
+		}
+		for _, p := range t.Permissions {
+			if p != PermissionRevealSecrets {
+				return nil, fmt.Errorf("token %d (%s) has unknown permission %q (expected %s)", i, t.Name, p, PermissionRevealSecrets)
+			}
+		}
+		if _, dup := seen[t.Token]; dup {
+			return nil, fmt.Errorf("token %d (%s) is a duplicate", i, t.Name)
+		}
+		seen[t.Token] = struct{}{}
+	}
+
+	return &TokenStore{tokens: tokens}, nil
+}
+
+// Lookup returns the identity for a bearer token.
+func (s *TokenStore) Lookup(token string) (Identity, bool) {
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return Identity{Name: t.Name, Role: t.Role, Permissions: t.Permissions}, true
+		}
+	}
+	return Identity{}, false
+}
+
+// Middleware rejects requests without a valid bearer token and attaches the
+// caller's identity to the request context.
+func Middleware(store *TokenStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="go-unifi-mcp"`)
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		identity, ok := store.Lookup(token)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="go-unifi-mcp", error="invalid_token"`)
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+	})
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a context carrying the caller's identity.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller's identity, if the request was authenticated.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// HasPermission reports whether the identity was granted a permission in
+// addition to its role's.
+func (i Identity) HasPermission(permission string) bool {
+	return slices.Contains(i.Permissions, permission)
+}
+
+// RevealSecrets reports whether the caller in ctx may see secret fields in
+// tool results. Callers with an identity need PermissionRevealSecrets; the
+// others (stdio, or HTTP without a token file) may if fallback is set.
+func RevealSecrets(ctx context.Context, fallback bool) bool {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return fallback
+	}
+	return identity.HasPermission(PermissionRevealSecrets)
+}
+
+// Permission names the permission a tool call requires, e.g. "delete:Network".
+func Permission(meta generated.ToolMetadata) string {
+	return meta.Category + ":" + meta.Resource
+}
+
+// adminResources are the resources only admins may change, besides settings.
+// Firewall rules, port forwards and RADIUS accounts and profiles decide who
+// can reach the network.
+var adminResources = map[string]bool{
+	"FirewallGroup": true, "FirewallRule": true, "FirewallZone": true, "FirewallZonePolicy": true,
+	"PortForward": true, "Account": true, "RADIUSProfile": true,
+}
+
+// Allows reports whether the role may call the tool described by meta, by its
+// category and resource.
+func (r Role) Allows(meta generated.ToolMetadata) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleOperator:
+		switch meta.Category {
+		case "list", "get":
+			return true
+		case "create", "update":
+			return !meta.IsSetting && !adminResources[meta.Resource]
+		}
+		return false
+	case RoleViewer:
+		return meta.Category == "list" || meta.Category == "get"
+	default:
+		return false
+	}
+}
+
+func (r Role) valid() bool {
+	return r == RoleViewer || r == RoleOperator || r == RoleAdmin
+}
+
+// Authorize checks the caller in ctx against the tool. Requests without an
+// identity (stdio, or HTTP without a token file) are not restricted.
+func Authorize(ctx context.Context, meta generated.ToolMetadata) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if identity.Role.Allows(meta) {
+		return nil
+	}
+	return fmt.Errorf("permission denied: role %q lacks permission %q required by tool %s",
+		identity.Role, Permission(meta), meta.Name)
+}
+
+// ToolMiddleware refuses calls the caller's role does not permit before the
+// wrapped handler runs.
+func ToolMiddleware(_ unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := Authorize(ctx, meta); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return next(ctx, req)
+	}
+}
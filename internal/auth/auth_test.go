@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTokenFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadTokenFile(t *testing.T) {
+	path := writeTokenFile(t, `
+tokens:
+  - name: alice
+    token: admin-token
+    role: admin
+  - name: oncall
+    token: viewer-token
+    role: viewer
+  - name: wifi
+    token: secrets-token
+    role: viewer
+    permissions: [reveal_secrets]
+`)
+
+	store, err := LoadTokenFile(path)
+	require.NoError(t, err)
+
+	identity, ok := store.Lookup("admin-token")
+	require.True(t, ok)
+	assert.Equal(t, Identity{Name: "alice", Role: RoleAdmin}, identity)
+
+	identity, ok = store.Lookup("viewer-token")
+	require.True(t, ok)
+	assert.Equal(t, RoleViewer, identity.Role)
+	assert.False(t, identity.HasPermission(PermissionRevealSecrets))
+
+	identity, ok = store.Lookup("secrets-token")
+	require.True(t, ok)
+	assert.True(t, identity.HasPermission(PermissionRevealSecrets))
+
+	_, ok = store.Lookup("unknown")
+	assert.False(t, ok)
+}
+
+func TestLoadTokenFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid yaml", "tokens: [", "failed to parse token file"},
+		{"no tokens", "tokens: []", "defines no tokens"},
+		{"empty token", "tokens:\n  - name: a\n    role: admin", "is empty"},
+		{"unknown role", "tokens:\n  - name: a\n    token: x\n    role: root", "unknown role"},
+		{"unknown permission", "tokens:\n  - {name: a, token: x, role: admin, permissions: [sudo]}", `unknown permission "sudo"`},
+		{"duplicate", "tokens:\n  - {name: a, token: x, role: admin}\n  - {name: b, token: x, role: viewer}", "duplicate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTokenFile(writeTokenFile(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := LoadTokenFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read token file")
+}
+
+func TestMiddleware(t *testing.T) {
+	store, err := NewTokenStore([]Token{{Name: "alice", Token: "secret", Role: RoleOperator}})
+	require.NoError(t, err)
+
+	var seen Identity
+	handler := Middleware(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = IdentityFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"missing header", "", http.StatusUnauthorized},
+		{"wrong scheme", "Basic secret", http.StatusUnauthorized},
+		{"invalid token", "Bearer nope", http.StatusUnauthorized},
+		{"valid token", "Bearer secret", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+
+	assert.Equal(t, Identity{Name: "alice", Role: RoleOperator}, seen)
+}
+
+func TestRoleAllows(t *testing.T) {
+	network := func(category string) generated.ToolMetadata {
+		return generated.ToolMetadata{Name: category + "_network", Category: category, Resource: "Network"}
+	}
+	setting := generated.ToolMetadata{Name: "update_setting_mgmt", Category: "update", Resource: "SettingMgmt", IsSetting: true}
+	firewall := generated.ToolMetadata{Name: "create_firewall_rule", Category: "create", Resource: "FirewallRule"}
+	portForward := generated.ToolMetadata{Name: "update_port_forward", Category: "update", Resource: "PortForward"}
+	radius := generated.ToolMetadata{Name: "get_radius_profile", Category: "get", Resource: "RADIUSProfile"}
+
+	tests := []struct {
+		role  Role
+		meta  generated.ToolMetadata
+		allow bool
+	}{
+		{RoleViewer, network("list"), true},
+		{RoleViewer, network("get"), true},
+		{RoleViewer, network("create"), false},
+		{RoleViewer, network("delete"), false},
+		{RoleOperator, network("create"), true},
+		{RoleOperator, network("update"), true},
+		{RoleOperator, setting, false},
+		{RoleOperator, firewall, false},
+		{RoleOperator, portForward, false},
+		{RoleOperator, radius, true},
+		{RoleViewer, radius, true},
+		{RoleOperator, network("delete"), false},
+		{RoleAdmin, network("delete"), true},
+		{RoleAdmin, setting, true},
+		{RoleAdmin, firewall, true},
+		{Role("root"), network("list"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+tt.meta.Name, func(t *testing.T) {
+			assert.Equal(t, tt.allow, tt.role.Allows(tt.meta))
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	meta := generated.ToolMetadata{Name: "delete_network", Category: "delete", Resource: "Network"}
+
+	// Unauthenticated contexts are not restricted.
+	assert.NoError(t, Authorize(context.Background(), meta))
+
+	ctx := WithIdentity(context.Background(), Identity{Name: "oncall", Role: RoleViewer})
+	err := Authorize(ctx, meta)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+	assert.Contains(t, err.Error(), `"viewer"`)
+	assert.Contains(t, err.Error(), `"delete:Network"`)
+	assert.Contains(t, err.Error(), "delete_network")
+}
+
+func TestRevealSecrets(t *testing.T) {
+	// Unauthenticated contexts follow the server's setting.
+	assert.False(t, RevealSecrets(context.Background(), false))
+	assert.True(t, RevealSecrets(context.Background(), true))
+
+	// Authenticated callers need the permission, whatever their role.
+	admin := WithIdentity(context.Background(), Identity{Name: "alice", Role: RoleAdmin})
+	assert.False(t, RevealSecrets(admin, true))
+	granted := WithIdentity(context.Background(), Identity{Name: "wifi", Role: RoleViewer, Permissions: []string{PermissionRevealSecrets}})
+	assert.True(t, RevealSecrets(granted, false))
+}
+
+func TestToolMiddleware(t *testing.T) {
+	meta := generated.ToolMetadata{Name: "delete_network", Category: "delete", Resource: "Network"}
+	called := false
+	handler := ToolMiddleware(nil, meta, func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	ctx := WithIdentity(context.Background(), Identity{Role: RoleOperator})
+	result, err := handler(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.False(t, called)
+
+	ctx = WithIdentity(context.Background(), Identity{Role: RoleAdmin})
+	result, err = handler(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.True(t, called)
+}
@@ -0,0 +1,212 @@
+// Package cache keeps the results of list and get calls in memory for a
+// short time, so repeated reads within an LLM session do not each make a
+// round trip to the controller. Any create, update or delete of a resource
+// drops the cached results for that resource on the same controller.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Arg is the tool argument that bypasses the cache for a call.
+const Arg = "no_cache"
+
+// ArgSchema is the JSON schema of the no_cache argument.
+var ArgSchema = map[string]any{
+	"type":        "boolean",
+	"description": "Fetch fresh data from the controller instead of a recently cached result",
+}
+
+// Cache holds the results of read-only tool calls per controller client,
+// tool and arguments, such as the site, ID and fields.
+type Cache struct {
+	ttl  time.Duration
+	ttls map[string]time.Duration // by lower-cased resource name
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[key]entry
+	// generations counts the invalidations of each resource, so a read
+	// that overlaps a change does not cache what it read before the change.
+	generations map[resourceKey]uint64
+}
+
+type resourceKey struct {
+	client   unifi.Client
+	resource string
+}
+
+type key struct {
+	resourceKey
+	tool string
+	args string // JSON of the arguments, which include the site and ID
+}
+
+type entry struct {
+	result  *mcp.CallToolResult
+	expires time.Time
+}
+
+// New returns a cache that keeps results for ttl, or for the duration given
+// in ttls for the resources named there (case-insensitively). A TTL of zero
+// disables caching of a resource. Unknown resource names are rejected.
+func New(ttl time.Duration, ttls map[string]time.Duration) (*Cache, error) {
+	known := make(map[string]bool)
+	for _, meta := range generated.AllToolMetadata {
+		known[strings.ToLower(meta.Resource)] = true
+	}
+	c := &Cache{
+		ttl:         ttl,
+		ttls:        make(map[string]time.Duration, len(ttls)),
+		now:         time.Now,
+		entries:     make(map[key]entry),
+		generations: make(map[resourceKey]uint64),
+	}
+	var unknown []string
+	for resource, d := range ttls {
+		if !known[strings.ToLower(resource)] {
+			unknown = append(unknown, resource)
+			continue
+		}
+		c.ttls[strings.ToLower(resource)] = d
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("cache TTL for unknown resource: %s", strings.Join(unknown, ", "))
+	}
+	return c, nil
+}
+
+// TTL returns how long results for the resource are kept.
+func (c *Cache) TTL(resource string) time.Duration {
+	if d, ok := c.ttls[strings.ToLower(resource)]; ok {
+		return d
+	}
+	return c.ttl
+}
+
+// Cached reports whether results of the tool are cached, and so whether it
+// accepts the no_cache argument.
+func (c *Cache) Cached(meta generated.ToolMetadata) bool {
+	return registry.IsReadOnly(meta) && c.TTL(meta.Resource) > 0
+}
+
+// Middleware serves list and get calls from the cache when it holds a fresh
+// result, and caches successful results otherwise. Calls with no_cache skip
+// the lookup but still refresh the cache. Every other call of a resource,
+// except dry runs, drops the cached results for that resource. It must run
+// after the site argument is resolved, so calls for the same site share
+// results.
+func (c *Cache) Middleware(client unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !registry.IsReadOnly(meta) {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if dryRun, _ := req.GetArguments()[generated.DryRunArg].(bool); !dryRun {
+				// Invalidate even if the call failed, since the controller
+				// may have applied part of it.
+				c.invalidate(client, meta.Resource)
+			}
+			return result, err
+		}
+	}
+	if !c.Cached(meta) {
+		return next
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		noCache, _ := args[Arg].(bool)
+		if _, ok := args[Arg]; ok {
+			newArgs := make(map[string]any, len(args))
+			for k, v := range args {
+				if k != Arg {
+					newArgs[k] = v
+				}
+			}
+			req.Params.Arguments = newArgs
+			args = newArgs
+		}
+
+		// Maps marshal with sorted keys, so equal arguments share a key.
+		argsJSON, err := json.Marshal(args)
+		if err != nil {
+			return next(ctx, req)
+		}
+		k := key{resourceKey: resourceKey{client, strings.ToLower(meta.Resource)}, tool: meta.Name, args: string(argsJSON)}
+		if !noCache {
+			if result, ok := c.get(k); ok {
+				return result, nil
+			}
+		}
+
+		generation := c.generation(k)
+		result, err := next(ctx, req)
+		if err == nil && result != nil && !result.IsError {
+			c.put(k, generation, result, c.TTL(meta.Resource))
+		}
+		return result, err
+	}
+}
+
+func (c *Cache) get(k key) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[k]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, k)
+		return nil, false
+	}
+	// Callers get their own copy of the result, sharing its content.
+	result := *e.result
+	return &result, true
+}
+
+func (c *Cache) generation(k key) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[k.resourceKey]
+}
+
+// put caches a result unless the resource was invalidated since generation.
+func (c *Cache) put(k key, generation uint64, result *mcp.CallToolResult, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[k.resourceKey] != generation {
+		return
+	}
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	stored := *result
+	c.entries[k] = entry{result: &stored, expires: now.Add(ttl)}
+}
+
+// invalidate drops the cached results for a resource of one controller.
+func (c *Cache) invalidate(client unifi.Client, resource string) {
+	rk := resourceKey{client, strings.ToLower(resource)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[rk]++
+	for k := range c.entries {
+		if k.resourceKey == rk {
+			delete(c.entries, k)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	listNetwork   = generated.ToolMetadata{Name: "list_network", Category: "list", Resource: "Network"}
+	getNetwork    = generated.ToolMetadata{Name: "get_network", Category: "get", Resource: "Network"}
+	updateNetwork = generated.ToolMetadata{Name: "update_network", Category: "update", Resource: "Network"}
+	listDevice    = generated.ToolMetadata{Name: "list_device", Category: "list", Resource: "Device"}
+)
+
+// counter returns a handler that counts its calls and returns the count.
+func counter(calls *int) server.ToolHandlerFunc {
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls++
+		if _, ok := req.GetArguments()[Arg]; ok {
+			return mcp.NewToolResultError("no_cache reached the handler"), nil
+		}
+		return mcp.NewToolResultText(string(rune('0' + *calls))), nil
+	}
+}
+
+func call(t *testing.T, handler server.ToolHandlerFunc, args map[string]any) string {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content)
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestNew_UnknownResource(t *testing.T) {
+	_, err := New(time.Minute, map[string]time.Duration{"device": time.Second, "Gadget": time.Second})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown resource: Gadget")
+}
+
+func TestCache_TTL(t *testing.T) {
+	c, err := New(time.Minute, map[string]time.Duration{"device": 5 * time.Second, "User": 0})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, c.TTL("Network"))
+	assert.Equal(t, 5*time.Second, c.TTL("Device"))
+	assert.True(t, c.Cached(listNetwork))
+	assert.False(t, c.Cached(updateNetwork))
+	assert.False(t, c.Cached(generated.ToolMetadata{Name: "list_user", Category: "list", Resource: "User"}))
+}
+
+func TestMiddleware_CachesReads(t *testing.T) {
+	c, err := New(time.Minute, map[string]time.Duration{"Device": time.Second})
+	require.NoError(t, err)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	client := servermocks.NewClient(t)
+
+	var calls int
+	list := c.Middleware(client, listNetwork, counter(&calls))
+	site := map[string]any{"site": "default"}
+	assert.Equal(t, "1", call(t, list, site))
+	assert.Equal(t, "1", call(t, list, site))
+	assert.Equal(t, "2", call(t, list, map[string]any{"site": "branch"}), "sites are cached separately")
+
+	// no_cache fetches and caches a fresh result.
+	assert.Equal(t, "3", call(t, list, map[string]any{"site": "default", Arg: true}))
+	assert.Equal(t, "3", call(t, list, site))
+
+	// Each ID is cached separately.
+	var gets int
+	get := c.Middleware(client, getNetwork, counter(&gets))
+	assert.Equal(t, "1", call(t, get, map[string]any{"site": "default", "id": "a"}))
+	assert.Equal(t, "2", call(t, get, map[string]any{"site": "default", "id": "b"}))
+	assert.Equal(t, "1", call(t, get, map[string]any{"site": "default", "id": "a"}))
+	assert.Equal(t, "3", call(t, get, map[string]any{"site": "default", "id": "a", "fields": []any{"name"}}),
+		"other arguments are cached separately")
+
+	// Entries expire after the resource's TTL.
+	var devices int
+	listDevices := c.Middleware(client, listDevice, counter(&devices))
+	assert.Equal(t, "1", call(t, listDevices, site))
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, "2", call(t, listDevices, site))
+	assert.Equal(t, "3", call(t, list, site), "other resources keep the default TTL")
+}
+
+func TestMiddleware_MutationsInvalidate(t *testing.T) {
+	c, err := New(time.Minute, nil)
+	require.NoError(t, err)
+	home, lab := servermocks.NewClient(t), servermocks.NewClient(t)
+
+	var networks, labNetworks, devices int
+	list := c.Middleware(home, listNetwork, counter(&networks))
+	labList := c.Middleware(lab, listNetwork, counter(&labNetworks))
+	listDevices := c.Middleware(home, listDevice, counter(&devices))
+	update := c.Middleware(home, updateNetwork, func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("{}"), nil
+	})
+	site := map[string]any{"site": "default"}
+	call(t, list, site)
+	call(t, labList, site)
+	call(t, listDevices, site)
+
+	// Dry runs change nothing.
+	call(t, update, map[string]any{"site": "default", "id": "a", generated.DryRunArg: true})
+	assert.Equal(t, "1", call(t, list, site))
+
+	// Only the updated resource of the same controller is dropped.
+	call(t, update, map[string]any{"site": "default", "id": "a"})
+	assert.Equal(t, "2", call(t, list, site))
+	assert.Equal(t, "1", call(t, labList, site))
+	assert.Equal(t, "1", call(t, listDevices, site))
+}
+
+func TestMiddleware_ReadOverlappingChangeIsNotCached(t *testing.T) {
+	c, err := New(time.Minute, nil)
+	require.NoError(t, err)
+	client := servermocks.NewClient(t)
+	update := c.Middleware(client, updateNetwork, func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("{}"), nil
+	})
+
+	var calls int
+	list := c.Middleware(client, listNetwork, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if calls == 0 {
+			// The update completes while the first list is in flight.
+			call(t, update, map[string]any{"site": "default", "id": "a"})
+		}
+		return counter(&calls)(ctx, req)
+	})
+	site := map[string]any{"site": "default"}
+	assert.Equal(t, "1", call(t, list, site))
+	assert.Equal(t, "2", call(t, list, site))
+	assert.Equal(t, "2", call(t, list, site))
+}
+
+func TestMiddleware_ErrorsAreNotCached(t *testing.T) {
+	c, err := New(time.Minute, nil)
+	require.NoError(t, err)
+	var calls int
+	list := c.Middleware(servermocks.NewClient(t), listNetwork, func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultError("controller unavailable"), nil
+	})
+
+	for range 2 {
+		result, err := list(context.Background(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	}
+	assert.Equal(t, 2, calls)
+}
@@ -1,36 +1,169 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// Transport names accepted by UNIFI_TRANSPORT.
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+)
+
+// Tool modes accepted by UNIFI_TOOL_MODE.
+const (
+	ToolModeLazy  = "lazy"
+	ToolModeEager = "eager"
+)
+
+// DefaultHTTPAddr is the listen address used by the HTTP transport when
+// UNIFI_HTTP_ADDR is not set.
+const DefaultHTTPAddr = "127.0.0.1:8080"
+
+// Defaults for the HTTP client used to talk to the controller.
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultRetries      = 2
+	DefaultRetryBackoff = 500 * time.Millisecond
+	DefaultToolTimeout  = 2 * time.Minute
+)
+
+// Defaults for pacing tool calls.
+const (
+	DefaultBatchConcurrency = 8
+	DefaultRateBurst        = 10
+)
+
+// DefaultAuditLogMaxMB is the size in megabytes at which the audit log is
+// rotated when UNIFI_AUDIT_LOG_MAX_MB is not set.
+const DefaultAuditLogMaxMB = 100
+
+// DefaultConfirmTools are the tool patterns that require user confirmation
+// when UNIFI_CONFIRM_TOOLS is not set: every delete, plus updates to
+// management settings, networks and firewall resources.
+var DefaultConfirmTools = []string{
+	"category:delete",
+	"update_setting_mgmt",
+	"update_network",
+	"resource:Firewall*",
+}
+
 var (
-	ErrMissingHost        = errors.New("UNIFI_HOST environment variable is required")
+	ErrMissingHost        = errors.New("UNIFI_HOST environment variable or profile host is required")
 	ErrMissingCredentials = errors.New("either UNIFI_API_KEY or both UNIFI_USERNAME and UNIFI_PASSWORD must be set")
+	ErrInvalidTransport   = errors.New("UNIFI_TRANSPORT must be one of: stdio, http")
+	ErrInvalidToolMode    = errors.New("UNIFI_TOOL_MODE must be one of: lazy, eager")
+	ErrInvalidProxy       = errors.New("UNIFI_PROXY must be a URL such as http://proxy.example.com:3128")
+	ErrInvalidFingerprint = errors.New("UNIFI_CERT_FINGERPRINT must be a SHA-256 fingerprint of 64 hex digits, optionally colon-separated")
 )
 
 // Config holds the MCP server configuration.
 type Config struct {
-	Host      string // UNIFI_HOST - UniFi controller URL
-	APIKey    string // UNIFI_API_KEY - API key auth (preferred)
-	Username  string // UNIFI_USERNAME - username/password auth
-	Password  string // UNIFI_PASSWORD - username/password auth
-	Site      string // UNIFI_SITE - site name (default: "default")
-	VerifySSL bool   // UNIFI_VERIFY_SSL - verify SSL certs (default: true)
+	Profile         string // UNIFI_PROFILE - name of the config file profile in use, empty without a file
+	Host            string // UNIFI_HOST - UniFi controller URL
+	APIKey          string // UNIFI_API_KEY - API key auth (preferred)
+	Username        string // UNIFI_USERNAME - username/password auth
+	Password        string // UNIFI_PASSWORD - username/password auth
+	Site            string // UNIFI_SITE - site name or description (default: "default")
+	VerifySSL       bool   // UNIFI_VERIFY_SSL - verify SSL certs (default: true)
+	CACert          string // UNIFI_CA_CERT - PEM file of the CA certificates to trust instead of the system roots
+	CertFingerprint string // UNIFI_CERT_FINGERPRINT - SHA-256 fingerprint the controller's certificate must match
+	ToolMode        string // UNIFI_TOOL_MODE - lazy or eager (default: "lazy")
+	Transport       string // UNIFI_TRANSPORT - stdio or http (default: "stdio")
+	HTTPAddr        string // UNIFI_HTTP_ADDR - HTTP listen address (default: "127.0.0.1:8080")
+	TokenFile       string // UNIFI_HTTP_TOKEN_FILE - bearer tokens and roles for the HTTP transport
+
+	Timeout      time.Duration // UNIFI_TIMEOUT - limit for connecting to the controller and awaiting each response, 0 for none (default: 30s)
+	Proxy        string        // UNIFI_PROXY - HTTP(S) proxy URL for controller requests (default: HTTPS_PROXY/HTTP_PROXY/NO_PROXY)
+	Retries      int           // UNIFI_RETRIES - retries of idempotent requests on 5xx, 429 and connection resets (default: 2)
+	RetryBackoff time.Duration // UNIFI_RETRY_BACKOFF - delay before the first retry, doubled for each further one (default: 500ms)
+	ToolTimeout  time.Duration // UNIFI_TOOL_TIMEOUT - deadline for each tool call, 0 for none (default: 2m)
+
+	BatchConcurrency int     // UNIFI_BATCH_CONCURRENCY - calls of a batch run at once (default: 8)
+	RateLimit        float64 // UNIFI_RATE_LIMIT - tool calls per second per controller, 0 for no limit (default: 0)
+	RateBurst        int     // UNIFI_RATE_BURST - tool calls allowed in a burst before UNIFI_RATE_LIMIT applies (default: 10)
+
+	CacheTTL  time.Duration            // UNIFI_CACHE_TTL - how long list/get results are cached, 0 to disable (default: 0)
+	CacheTTLs map[string]time.Duration // UNIFI_CACHE_TTLS - per-resource cache TTLs overriding CacheTTL, e.g. "Device=5s,Network=5m"
+
+	OutputFormat string // UNIFI_OUTPUT_FORMAT - json, json-indent, yaml, csv or markdown (default: "json-indent")
+
+	APIKeyFile        string // UNIFI_API_KEY_FILE - file holding the API key, e.g. a Docker or Kubernetes secret
+	PasswordFile      string // UNIFI_PASSWORD_FILE - file holding the password
+	CredentialCommand string // UNIFI_CREDENTIAL_COMMAND - command printing the API key, or the password if a username is set
+
+	// APIKeySecret is set when the API key comes from a file or the
+	// credential command. Its value follows ReloadSecrets; APIKey keeps the
+	// value read at load time.
+	APIKeySecret *Secret
+
+	AllowedSites  []string // UNIFI_ALLOWED_SITES - comma-separated site allowlist (default: all sites)
+	ReadOnly      bool     // UNIFI_READ_ONLY - expose only list/get tools (default: false)
+	DryRun        bool     // UNIFI_DRY_RUN - preview every create/update/delete without applying it (default: false)
+	ConfirmTools  []string // UNIFI_CONFIRM_TOOLS - tool patterns that need user confirmation, "none" to disable
+	RevealSecrets bool     // UNIFI_REVEAL_SECRETS - show passphrases and other secrets in tool results to callers without a token (default: false)
+
+	AuditLog         string // UNIFI_AUDIT_LOG - JSONL file recording every create/update/delete (default: disabled)
+	AuditLogMaxBytes int64  // UNIFI_AUDIT_LOG_MAX_MB - rotate the audit log at this size, 0 to never rotate (default: 100 MB)
+	UndoHistory      int    // UNIFI_UNDO_HISTORY - number of changes kept for list_changes and undo (default: 0, disabled)
+
+	ToolsInclude []string // UNIFI_TOOLS_INCLUDE - comma-separated tool patterns to expose (default: all)
+	ToolsExclude []string // UNIFI_TOOLS_EXCLUDE - comma-separated tool patterns to hide
+
+	// Controllers are the other config file profiles served alongside this
+	// one (UNIFI_CONTROLLERS or the file's controllers list). Only their
+	// connection settings, Site and AllowedSites are used.
+	Controllers []*Config
 }
 
-// Load loads configuration from environment variables.
+// Load loads configuration from the configuration file, if any, and
+// environment variables. See LoadFile.
 func Load() (*Config, error) {
-	cfg := &Config{
-		Host:      os.Getenv("UNIFI_HOST"),
-		APIKey:    os.Getenv("UNIFI_API_KEY"),
-		Username:  os.Getenv("UNIFI_USERNAME"),
-		Password:  os.Getenv("UNIFI_PASSWORD"),
-		Site:      os.Getenv("UNIFI_SITE"),
-		VerifySSL: true,
+	return LoadFile("", "")
+}
+
+// LoadFile loads the named profile from the configuration file at path and
+// applies environment variables on top of it. An empty path falls back to
+// UNIFI_CONFIG and then to DefaultPath if that file exists; without a file
+// only environment variables are used. An empty profile falls back to
+// UNIFI_PROFILE and then the file's default profile.
+func LoadFile(path, profile string) (*Config, error) {
+	cfg := &Config{VerifySSL: true}
+	if err := loadProfile(cfg, path, profile); err != nil {
+		return nil, err
+	}
+
+	setFromEnv(&cfg.Host, "UNIFI_HOST")
+	setFromEnv(&cfg.Username, "UNIFI_USERNAME")
+	if err := setSecretFromEnv(&cfg.APIKey, &cfg.APIKeyFile, "UNIFI_API_KEY"); err != nil {
+		return nil, err
 	}
+	if err := setSecretFromEnv(&cfg.Password, &cfg.PasswordFile, "UNIFI_PASSWORD"); err != nil {
+		return nil, err
+	}
+	setFromEnv(&cfg.CredentialCommand, "UNIFI_CREDENTIAL_COMMAND")
+	setFromEnv(&cfg.Site, "UNIFI_SITE")
+	setFromEnv(&cfg.CACert, "UNIFI_CA_CERT")
+	setFromEnv(&cfg.CertFingerprint, "UNIFI_CERT_FINGERPRINT")
+	setFromEnv(&cfg.Proxy, "UNIFI_PROXY")
+	setFromEnv(&cfg.ToolMode, "UNIFI_TOOL_MODE")
+	setFromEnv(&cfg.Transport, "UNIFI_TRANSPORT")
+	setFromEnv(&cfg.HTTPAddr, "UNIFI_HTTP_ADDR")
+	setFromEnv(&cfg.TokenFile, "UNIFI_HTTP_TOKEN_FILE")
+	setFromEnv(&cfg.AuditLog, "UNIFI_AUDIT_LOG")
+	setFromEnv(&cfg.OutputFormat, "UNIFI_OUTPUT_FORMAT")
+	setListFromEnv(&cfg.AllowedSites, "UNIFI_ALLOWED_SITES")
+	setListFromEnv(&cfg.ToolsInclude, "UNIFI_TOOLS_INCLUDE")
+	setListFromEnv(&cfg.ToolsExclude, "UNIFI_TOOLS_EXCLUDE")
 
 	// Parse UNIFI_VERIFY_SSL
 	if v := os.Getenv("UNIFI_VERIFY_SSL"); v != "" {
@@ -41,20 +174,255 @@ func Load() (*Config, error) {
 		cfg.VerifySSL = parsed
 	}
 
+	// Parse UNIFI_READ_ONLY
+	if v := os.Getenv("UNIFI_READ_ONLY"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("UNIFI_READ_ONLY must be a boolean (true/false)")
+		}
+		cfg.ReadOnly = parsed
+	}
+
+	// Parse UNIFI_DRY_RUN
+	if v := os.Getenv("UNIFI_DRY_RUN"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("UNIFI_DRY_RUN must be a boolean (true/false)")
+		}
+		cfg.DryRun = parsed
+	}
+
+	// Parse UNIFI_REVEAL_SECRETS
+	if v := os.Getenv("UNIFI_REVEAL_SECRETS"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("UNIFI_REVEAL_SECRETS must be a boolean (true/false)")
+		}
+		cfg.RevealSecrets = parsed
+	}
+
+	// Parse UNIFI_AUDIT_LOG_MAX_MB
+	cfg.AuditLogMaxBytes = DefaultAuditLogMaxMB << 20
+	if v := os.Getenv("UNIFI_AUDIT_LOG_MAX_MB"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, errors.New("UNIFI_AUDIT_LOG_MAX_MB must be a non-negative integer")
+		}
+		cfg.AuditLogMaxBytes = parsed << 20
+	}
+
+	// Parse UNIFI_UNDO_HISTORY
+	if v := os.Getenv("UNIFI_UNDO_HISTORY"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return nil, errors.New("UNIFI_UNDO_HISTORY must be a non-negative integer")
+		}
+		cfg.UndoHistory = parsed
+	}
+
+	// Parse UNIFI_TIMEOUT, UNIFI_RETRIES, UNIFI_RETRY_BACKOFF and
+	// UNIFI_TOOL_TIMEOUT
+	cfg.Timeout = DefaultTimeout
+	if err := durationFromEnv(&cfg.Timeout, "UNIFI_TIMEOUT"); err != nil {
+		return nil, err
+	}
+	cfg.Retries = DefaultRetries
+	if v := os.Getenv("UNIFI_RETRIES"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return nil, errors.New("UNIFI_RETRIES must be a non-negative integer")
+		}
+		cfg.Retries = parsed
+	}
+	cfg.RetryBackoff = DefaultRetryBackoff
+	if err := durationFromEnv(&cfg.RetryBackoff, "UNIFI_RETRY_BACKOFF"); err != nil {
+		return nil, err
+	}
+	cfg.ToolTimeout = DefaultToolTimeout
+	if err := durationFromEnv(&cfg.ToolTimeout, "UNIFI_TOOL_TIMEOUT"); err != nil {
+		return nil, err
+	}
+
+	// Parse UNIFI_BATCH_CONCURRENCY, UNIFI_RATE_LIMIT and UNIFI_RATE_BURST
+	cfg.BatchConcurrency = DefaultBatchConcurrency
+	if v := os.Getenv("UNIFI_BATCH_CONCURRENCY"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return nil, errors.New("UNIFI_BATCH_CONCURRENCY must be a positive integer")
+		}
+		cfg.BatchConcurrency = parsed
+	}
+	if v := os.Getenv("UNIFI_RATE_LIMIT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+			return nil, errors.New("UNIFI_RATE_LIMIT must be a non-negative number of calls per second")
+		}
+		cfg.RateLimit = parsed
+	}
+	cfg.RateBurst = DefaultRateBurst
+	if v := os.Getenv("UNIFI_RATE_BURST"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return nil, errors.New("UNIFI_RATE_BURST must be a positive integer")
+		}
+		cfg.RateBurst = parsed
+	}
+
+	// Parse UNIFI_CACHE_TTL and UNIFI_CACHE_TTLS
+	if err := durationFromEnv(&cfg.CacheTTL, "UNIFI_CACHE_TTL"); err != nil {
+		return nil, err
+	}
+	if v := os.Getenv("UNIFI_CACHE_TTLS"); v != "" {
+		ttls, err := parseCacheTTLs(v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CacheTTLs = ttls
+	}
+
+	// Parse UNIFI_CONFIRM_TOOLS
+	switch v := os.Getenv("UNIFI_CONFIRM_TOOLS"); {
+	case v == "":
+		cfg.ConfirmTools = DefaultConfirmTools
+	case strings.EqualFold(strings.TrimSpace(v), "none"):
+		cfg.ConfirmTools = nil
+	default:
+		cfg.ConfirmTools = splitList(v)
+	}
+
 	// Set default site
 	if cfg.Site == "" {
 		cfg.Site = "default"
 	}
 
+	// Set default tool mode
+	if cfg.ToolMode == "" {
+		cfg.ToolMode = ToolModeLazy
+	}
+
+	// Set default transport and listen address
+	if cfg.Transport == "" {
+		cfg.Transport = TransportStdio
+	}
+	if cfg.HTTPAddr == "" {
+		cfg.HTTPAddr = DefaultHTTPAddr
+	}
+
+	if err := cfg.readSecrets(); err != nil {
+		return nil, err
+	}
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 
+	// The other controllers share the HTTP client settings, except for a
+	// proxy of their own.
+	for _, ctrl := range cfg.Controllers {
+		ctrl.Timeout = cfg.Timeout
+		ctrl.Retries = cfg.Retries
+		ctrl.RetryBackoff = cfg.RetryBackoff
+		if ctrl.Proxy == "" {
+			ctrl.Proxy = cfg.Proxy
+		}
+	}
+
 	return cfg, nil
 }
 
-// Validate checks required configuration.
+// setFromEnv overrides *field with the environment variable, if set.
+func setFromEnv(field *string, name string) {
+	if v := os.Getenv(name); v != "" {
+		*field = v
+	}
+}
+
+// setSecretFromEnv overrides the credential *value or the file *file it is
+// read from with the environment variable name or name_FILE. Setting one
+// clears the other, so the environment takes precedence over a profile
+// either way.
+func setSecretFromEnv(value, file *string, name string) error {
+	v, f := os.Getenv(name), os.Getenv(name+"_FILE")
+	switch {
+	case v != "" && f != "":
+		return fmt.Errorf("only one of %s and %s_FILE may be set", name, name)
+	case v != "":
+		*value, *file = v, ""
+	case f != "":
+		*value, *file = "", f
+	}
+	return nil
+}
+
+// parseCacheTTLs parses a comma-separated list of resource=duration pairs.
+func parseCacheTTLs(v string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for _, item := range splitList(v) {
+		resource, value, ok := strings.Cut(item, "=")
+		resource = strings.TrimSpace(resource)
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || resource == "" || err != nil || d < 0 {
+			return nil, fmt.Errorf("UNIFI_CACHE_TTLS entry %q must be resource=duration, such as Device=5s", item)
+		}
+		ttls[resource] = d
+	}
+	return ttls, nil
+}
+
+// durationFromEnv overrides *field with the environment variable, if set.
+// The value is a Go duration such as "30s" or "1m30s", and must not be
+// negative.
+func durationFromEnv(field *time.Duration, name string) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%s must be a non-negative duration such as 30s or 2m", name)
+	}
+	*field = parsed
+	return nil
+}
+
+// setListFromEnv overrides *field with the comma-separated environment
+// variable, if set.
+func setListFromEnv(field *[]string, name string) {
+	if v := os.Getenv(name); v != "" {
+		*field = splitList(v)
+	}
+}
+
+// splitList splits a comma-separated value, dropping empty entries.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readSecrets is ReadSecrets with errors naming the config file profile.
+func (c *Config) readSecrets() error {
+	err := c.ReadSecrets()
+	if err != nil && c.Profile != "" {
+		return fmt.Errorf("profile %q: %w", c.Profile, err)
+	}
+	return err
+}
+
+// Validate checks required configuration. Errors name the config file
+// profile the settings came from, if any.
 func (c *Config) Validate() error {
+	err := c.validate()
+	if err != nil && c.Profile != "" {
+		return fmt.Errorf("profile %q: %w", c.Profile, err)
+	}
+	return err
+}
+
+func (c *Config) validate() error {
 	if c.Host == "" {
 		return ErrMissingHost
 	}
@@ -63,9 +431,44 @@ func (c *Config) Validate() error {
 		return ErrMissingCredentials
 	}
 
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return ErrInvalidProxy
+		}
+	}
+
+	if c.CertFingerprint != "" {
+		if _, err := ParseFingerprint(c.CertFingerprint); err != nil {
+			return err
+		}
+	}
+
+	switch c.Transport {
+	case "", TransportStdio, TransportHTTP:
+	default:
+		return ErrInvalidTransport
+	}
+
+	switch c.ToolMode {
+	case "", ToolModeLazy, ToolModeEager:
+	default:
+		return ErrInvalidToolMode
+	}
+
 	return nil
 }
 
+// ParseFingerprint parses a SHA-256 certificate fingerprint given as hex
+// digits, optionally separated by colons as printed by
+// "openssl x509 -fingerprint -sha256".
+func ParseFingerprint(s string) ([]byte, error) {
+	fingerprint, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(s), ":", ""))
+	if err != nil || len(fingerprint) != sha256.Size {
+		return nil, ErrInvalidFingerprint
+	}
+	return fingerprint, nil
+}
+
 // UseAPIKey returns true if API key auth should be used.
 func (c *Config) UseAPIKey() bool {
 	return c.APIKey != ""
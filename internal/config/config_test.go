@@ -1,7 +1,9 @@
 package config
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -92,3 +94,358 @@ func TestLoad_InvalidVerifySSL(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "UNIFI_VERIFY_SSL")
 }
+
+func TestLoad_TransportDefaults(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_TRANSPORT", "")
+	t.Setenv("UNIFI_HTTP_ADDR", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, TransportStdio, cfg.Transport)
+	assert.Equal(t, DefaultHTTPAddr, cfg.HTTPAddr)
+}
+
+func TestLoad_HTTPTransport(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_TRANSPORT", "http")
+	t.Setenv("UNIFI_HTTP_ADDR", "127.0.0.1:9000")
+	t.Setenv("UNIFI_HTTP_TOKEN_FILE", "/etc/go-unifi-mcp/tokens.yaml")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, TransportHTTP, cfg.Transport)
+	assert.Equal(t, "127.0.0.1:9000", cfg.HTTPAddr)
+	assert.Equal(t, "/etc/go-unifi-mcp/tokens.yaml", cfg.TokenFile)
+}
+
+func TestLoad_InvalidTransport(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_TRANSPORT", "carrier-pigeon")
+
+	_, err := Load()
+	assert.ErrorIs(t, err, ErrInvalidTransport)
+}
+
+func TestLoad_AllowedSites(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_ALLOWED_SITES", "default, Branch Office,,")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "Branch Office"}, cfg.AllowedSites)
+}
+
+func TestLoad_ReadOnly(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_READ_ONLY", "true")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.ReadOnly)
+}
+
+func TestLoad_InvalidReadOnly(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_READ_ONLY", "sometimes")
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "UNIFI_READ_ONLY")
+}
+
+func TestLoad_DryRun(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_DRY_RUN", "1")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.DryRun)
+}
+
+func TestLoad_InvalidDryRun(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_DRY_RUN", "maybe")
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "UNIFI_DRY_RUN")
+}
+
+func TestLoad_RevealSecrets(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.RevealSecrets)
+
+	t.Setenv("UNIFI_REVEAL_SECRETS", "true")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.RevealSecrets)
+
+	t.Setenv("UNIFI_REVEAL_SECRETS", "maybe")
+	_, err = Load()
+	assert.ErrorContains(t, err, "UNIFI_REVEAL_SECRETS")
+}
+
+func TestLoad_ConfirmTools(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfirmTools, cfg.ConfirmTools)
+
+	t.Setenv("UNIFI_CONFIRM_TOOLS", "category:delete, update_wlan")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"category:delete", "update_wlan"}, cfg.ConfirmTools)
+
+	t.Setenv("UNIFI_CONFIRM_TOOLS", "none")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ConfirmTools)
+}
+
+func TestLoad_AuditLog(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.AuditLog)
+	assert.Equal(t, int64(100<<20), cfg.AuditLogMaxBytes)
+
+	t.Setenv("UNIFI_AUDIT_LOG", "/var/log/unifi-mcp/audit.jsonl")
+	t.Setenv("UNIFI_AUDIT_LOG_MAX_MB", "5")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "/var/log/unifi-mcp/audit.jsonl", cfg.AuditLog)
+	assert.Equal(t, int64(5<<20), cfg.AuditLogMaxBytes)
+}
+
+func TestLoad_InvalidAuditLogMaxMB(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	for _, v := range []string{"big", "-1"} {
+		t.Setenv("UNIFI_AUDIT_LOG_MAX_MB", v)
+		_, err := Load()
+		assert.Error(t, err, v)
+		assert.Contains(t, err.Error(), "UNIFI_AUDIT_LOG_MAX_MB")
+	}
+}
+
+func TestLoad_UndoHistory(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.UndoHistory)
+
+	t.Setenv("UNIFI_UNDO_HISTORY", "50")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 50, cfg.UndoHistory)
+
+	for _, v := range []string{"many", "-1"} {
+		t.Setenv("UNIFI_UNDO_HISTORY", v)
+		_, err := Load()
+		assert.Error(t, err, v)
+		assert.Contains(t, err.Error(), "UNIFI_UNDO_HISTORY")
+	}
+}
+
+func TestLoad_InvalidToolMode(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_TOOL_MODE", "sometimes")
+
+	_, err := Load()
+	assert.ErrorIs(t, err, ErrInvalidToolMode)
+}
+
+func TestLoad_ToolFilters(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_TOOLS_INCLUDE", "resource:Firewall*, resource:Network")
+	t.Setenv("UNIFI_TOOLS_EXCLUDE", "category:delete")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"resource:Firewall*", "resource:Network"}, cfg.ToolsInclude)
+	assert.Equal(t, []string{"category:delete"}, cfg.ToolsExclude)
+}
+
+func TestLoad_CertificateTrust(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+	t.Setenv("UNIFI_CA_CERT", "/etc/ssl/unifi-ca.pem")
+	t.Setenv("UNIFI_CERT_FINGERPRINT", "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/ssl/unifi-ca.pem", cfg.CACert)
+	assert.NotEmpty(t, cfg.CertFingerprint)
+
+	for _, v := range []string{"abcd", "zz" + strings.Repeat("00", 31), strings.Repeat("00", 33)} {
+		t.Setenv("UNIFI_CERT_FINGERPRINT", v)
+		_, err = Load()
+		assert.ErrorIs(t, err, ErrInvalidFingerprint, v)
+	}
+}
+
+func TestLoad_HTTPTuning(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTimeout, cfg.Timeout)
+	assert.Empty(t, cfg.Proxy)
+	assert.Equal(t, DefaultRetries, cfg.Retries)
+	assert.Equal(t, DefaultRetryBackoff, cfg.RetryBackoff)
+	assert.Equal(t, DefaultToolTimeout, cfg.ToolTimeout)
+
+	t.Setenv("UNIFI_TIMEOUT", "10s")
+	t.Setenv("UNIFI_PROXY", "http://proxy.example.com:3128")
+	t.Setenv("UNIFI_RETRIES", "0")
+	t.Setenv("UNIFI_RETRY_BACKOFF", "1s")
+	t.Setenv("UNIFI_TOOL_TIMEOUT", "0")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, cfg.Timeout)
+	assert.Equal(t, "http://proxy.example.com:3128", cfg.Proxy)
+	assert.Zero(t, cfg.Retries)
+	assert.Equal(t, time.Second, cfg.RetryBackoff)
+	assert.Zero(t, cfg.ToolTimeout)
+}
+
+func TestLoad_InvalidHTTPTuning(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	for name, values := range map[string][]string{
+		"UNIFI_TIMEOUT":       {"30", "-1s"},
+		"UNIFI_RETRIES":       {"often", "-1"},
+		"UNIFI_RETRY_BACKOFF": {"soon"},
+		"UNIFI_TOOL_TIMEOUT":  {"-2m"},
+	} {
+		for _, v := range values {
+			t.Setenv(name, v)
+			_, err := Load()
+			require.Error(t, err, v)
+			assert.Contains(t, err.Error(), name)
+		}
+		t.Setenv(name, "")
+	}
+
+	for _, v := range []string{"proxy.example.com:3128", "http://", "://bad"} {
+		t.Setenv("UNIFI_PROXY", v)
+		_, err := Load()
+		assert.ErrorIs(t, err, ErrInvalidProxy, v)
+	}
+}
+
+func TestLoad_Pacing(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBatchConcurrency, cfg.BatchConcurrency)
+	assert.Zero(t, cfg.RateLimit)
+	assert.Equal(t, DefaultRateBurst, cfg.RateBurst)
+
+	t.Setenv("UNIFI_BATCH_CONCURRENCY", "2")
+	t.Setenv("UNIFI_RATE_LIMIT", "0.5")
+	t.Setenv("UNIFI_RATE_BURST", "3")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.BatchConcurrency)
+	assert.Equal(t, 0.5, cfg.RateLimit)
+	assert.Equal(t, 3, cfg.RateBurst)
+
+	for name, values := range map[string][]string{
+		"UNIFI_BATCH_CONCURRENCY": {"0", "many"},
+		"UNIFI_RATE_LIMIT":        {"-1", "fast", "NaN", "Inf"},
+		"UNIFI_RATE_BURST":        {"0"},
+	} {
+		for _, v := range values {
+			t.Setenv(name, v)
+			_, err := Load()
+			require.Error(t, err, v)
+			assert.Contains(t, err.Error(), name)
+		}
+		t.Setenv(name, "")
+	}
+}
+
+func TestParseFingerprint(t *testing.T) {
+	want := make([]byte, 32)
+	want[0], want[31] = 0xab, 0x01
+
+	for _, v := range []string{
+		"AB" + strings.Repeat(":00", 30) + ":01",
+		"ab" + strings.Repeat("00", 30) + "01\n",
+	} {
+		got, err := ParseFingerprint(v)
+		require.NoError(t, err, v)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestLoad_OutputFormat(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.OutputFormat)
+
+	t.Setenv("UNIFI_OUTPUT_FORMAT", "markdown")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "markdown", cfg.OutputFormat)
+}
+
+func TestLoad_Cache(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "test-api-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.CacheTTL)
+	assert.Empty(t, cfg.CacheTTLs)
+
+	t.Setenv("UNIFI_CACHE_TTL", "30s")
+	t.Setenv("UNIFI_CACHE_TTLS", "Device=5s, Network = 5m,User=0")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.CacheTTL)
+	assert.Equal(t, map[string]time.Duration{"Device": 5 * time.Second, "Network": 5 * time.Minute, "User": 0}, cfg.CacheTTLs)
+
+	for _, v := range []string{"Device", "Device=soon", "=5s", "Device=-1s"} {
+		t.Setenv("UNIFI_CACHE_TTLS", v)
+		_, err := Load()
+		require.Error(t, err, v)
+		assert.Contains(t, err.Error(), "UNIFI_CACHE_TTLS")
+	}
+	t.Setenv("UNIFI_CACHE_TTLS", "")
+
+	t.Setenv("UNIFI_CACHE_TTL", "-1s")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "UNIFI_CACHE_TTL")
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the on-disk format of the configuration file. Each profile
+// describes one controller; environment variables override its values.
+//
+//	default_profile: home
+//	controllers: [home, lab]
+//	profiles:
+//	  home:
+//	    host: https://192.168.1.1
+//	    api_key: ...
+//	  lab:
+//	    host: https://10.0.0.1
+//	    username: admin
+//	    password: ...
+//	    verify_ssl: false
+//
+// Controllers lists profiles that one server serves side by side; the
+// selected profile is the default controller and provides the server-wide
+// settings such as tool mode and filters.
+type File struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Controllers    []string           `yaml:"controllers"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// Profile holds the settings of one controller. Unset fields keep their
+// defaults.
+type Profile struct {
+	Host              string   `yaml:"host"`
+	APIKey            string   `yaml:"api_key"`
+	APIKeyFile        string   `yaml:"api_key_file"`
+	Username          string   `yaml:"username"`
+	Password          string   `yaml:"password"`
+	PasswordFile      string   `yaml:"password_file"`
+	CredentialCommand string   `yaml:"credential_command"`
+	Site              string   `yaml:"site"`
+	AllowedSites      []string `yaml:"allowed_sites"`
+	VerifySSL         *bool    `yaml:"verify_ssl"`
+	CACert            string   `yaml:"ca_cert"`
+	CertFingerprint   string   `yaml:"cert_fingerprint"`
+	Proxy             string   `yaml:"proxy"`
+	ToolMode          string   `yaml:"tool_mode"`
+	ToolsInclude      []string `yaml:"tools_include"`
+	ToolsExclude      []string `yaml:"tools_exclude"`
+}
+
+// DefaultPath returns the configuration file read when neither -config nor
+// UNIFI_CONFIG is given: go-unifi-mcp/config.yaml in the user's config
+// directory. It returns "" if that directory is unknown.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-unifi-mcp", "config.yaml")
+}
+
+// ReadFile reads and parses a YAML configuration file.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if len(file.Profiles) == 0 {
+		return nil, fmt.Errorf("config file %s defines no profiles", path)
+	}
+	return &file, nil
+}
+
+// Profile returns the named profile. An empty name selects the file's
+// default profile, or the only profile if there is just one.
+func (f *File) Profile(name string) (string, Profile, error) {
+	if name == "" {
+		name = f.DefaultProfile
+	}
+	if name == "" {
+		if len(f.Profiles) != 1 {
+			return "", Profile{}, fmt.Errorf("config file defines several profiles (%s); select one with -profile or UNIFI_PROFILE",
+				strings.Join(f.profileNames(), ", "))
+		}
+		for only := range f.Profiles {
+			name = only
+		}
+	}
+
+	p, ok := f.Profiles[name]
+	if !ok {
+		return "", Profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(f.profileNames(), ", "))
+	}
+	return name, p, nil
+}
+
+func (f *File) profileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apply copies the profile's values into cfg.
+func (p Profile) apply(cfg *Config) {
+	cfg.Host = p.Host
+	cfg.APIKey = p.APIKey
+	cfg.APIKeyFile = p.APIKeyFile
+	cfg.Username = p.Username
+	cfg.Password = p.Password
+	cfg.PasswordFile = p.PasswordFile
+	cfg.CredentialCommand = p.CredentialCommand
+	cfg.Site = p.Site
+	cfg.AllowedSites = p.AllowedSites
+	if p.VerifySSL != nil {
+		cfg.VerifySSL = *p.VerifySSL
+	}
+	cfg.CACert = p.CACert
+	cfg.CertFingerprint = p.CertFingerprint
+	cfg.Proxy = p.Proxy
+	cfg.ToolMode = p.ToolMode
+	cfg.ToolsInclude = p.ToolsInclude
+	cfg.ToolsExclude = p.ToolsExclude
+}
+
+// loadProfile applies the selected profile of the configuration file to cfg
+// and loads the other controllers it is served with. An empty path falls back
+// to UNIFI_CONFIG and then DefaultPath, which is skipped if it does not exist.
+// An empty profile falls back to UNIFI_PROFILE.
+func loadProfile(cfg *Config, path, profile string) error {
+	if path == "" {
+		path = os.Getenv("UNIFI_CONFIG")
+	}
+	if profile == "" {
+		profile = os.Getenv("UNIFI_PROFILE")
+	}
+	controllers := splitList(os.Getenv("UNIFI_CONTROLLERS"))
+	if path == "" {
+		path = DefaultPath()
+		if _, err := os.Stat(path); path == "" || errors.Is(err, os.ErrNotExist) {
+			if profile != "" {
+				return fmt.Errorf("profile %q selected but no config file found", profile)
+			}
+			if len(controllers) > 0 {
+				return errors.New("UNIFI_CONTROLLERS is set but no config file found")
+			}
+			return nil
+		}
+	}
+
+	file, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+	name, p, err := file.Profile(profile)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	p.apply(cfg)
+	cfg.Profile = name
+
+	if len(controllers) == 0 {
+		controllers = file.Controllers
+	}
+	for _, other := range controllers {
+		if other == name {
+			continue
+		}
+		p, ok := file.Profiles[other]
+		if !ok {
+			return fmt.Errorf("%s: unknown controller profile %q (available: %s)", path, other, strings.Join(file.profileNames(), ", "))
+		}
+		ctrl := &Config{Profile: other, VerifySSL: true}
+		p.apply(ctrl)
+		if ctrl.Site == "" {
+			ctrl.Site = "default"
+		}
+		if err := ctrl.readSecrets(); err != nil {
+			return err
+		}
+		if err := ctrl.Validate(); err != nil {
+			return err
+		}
+		cfg.Controllers = append(cfg.Controllers, ctrl)
+	}
+	return nil
+}
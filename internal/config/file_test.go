@@ -0,0 +1,233 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `
+default_profile: home
+profiles:
+  home:
+    host: https://192.168.1.1
+    api_key: home-key
+    site: Home
+  lab:
+    host: https://10.0.0.1
+    username: admin
+    password: lab-password
+    verify_ssl: false
+    ca_cert: /etc/ssl/lab-ca.pem
+    cert_fingerprint: "AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB:AB"
+    tool_mode: eager
+    allowed_sites: [default]
+    tools_include: ["resource:Network"]
+    tools_exclude: ["category:delete"]
+  broken:
+    host: https://10.0.0.2
+`
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile_DefaultProfile(t *testing.T) {
+	path := writeConfigFile(t, testConfigFile)
+
+	cfg, err := LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "home", cfg.Profile)
+	assert.Equal(t, "https://192.168.1.1", cfg.Host)
+	assert.Equal(t, "home-key", cfg.APIKey)
+	assert.Equal(t, "Home", cfg.Site)
+	assert.True(t, cfg.VerifySSL)
+	assert.Equal(t, ToolModeLazy, cfg.ToolMode)
+}
+
+func TestLoadFile_NamedProfile(t *testing.T) {
+	path := writeConfigFile(t, testConfigFile)
+
+	cfg, err := LoadFile(path, "lab")
+	require.NoError(t, err)
+	assert.Equal(t, "lab", cfg.Profile)
+	assert.Equal(t, "https://10.0.0.1", cfg.Host)
+	assert.True(t, cfg.UseUserPass())
+	assert.Equal(t, "default", cfg.Site)
+	assert.False(t, cfg.VerifySSL)
+	assert.Equal(t, "/etc/ssl/lab-ca.pem", cfg.CACert)
+	assert.Len(t, cfg.CertFingerprint, 95)
+	assert.Equal(t, ToolModeEager, cfg.ToolMode)
+	assert.Equal(t, []string{"default"}, cfg.AllowedSites)
+	assert.Equal(t, []string{"resource:Network"}, cfg.ToolsInclude)
+	assert.Equal(t, []string{"category:delete"}, cfg.ToolsExclude)
+}
+
+func TestLoadFile_EnvironmentOverridesProfile(t *testing.T) {
+	t.Setenv("UNIFI_CONFIG", writeConfigFile(t, testConfigFile))
+	t.Setenv("UNIFI_PROFILE", "lab")
+	t.Setenv("UNIFI_HOST", "https://10.0.0.9")
+	t.Setenv("UNIFI_VERIFY_SSL", "true")
+	t.Setenv("UNIFI_TOOLS_EXCLUDE", "resource:Hotspot*")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "lab", cfg.Profile)
+	assert.Equal(t, "https://10.0.0.9", cfg.Host)
+	assert.Equal(t, "admin", cfg.Username)
+	assert.True(t, cfg.VerifySSL)
+	assert.Equal(t, []string{"resource:Hotspot*"}, cfg.ToolsExclude)
+}
+
+func TestLoadFile_ValidationNamesProfile(t *testing.T) {
+	path := writeConfigFile(t, testConfigFile)
+
+	_, err := LoadFile(path, "broken")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrMissingCredentials)
+	assert.Contains(t, err.Error(), `profile "broken"`)
+
+	// Credentials from the environment complete the profile.
+	t.Setenv("UNIFI_API_KEY", "env-key")
+	cfg, err := LoadFile(path, "broken")
+	require.NoError(t, err)
+	assert.Equal(t, "env-key", cfg.APIKey)
+}
+
+func TestLoadFile_ProfileSelectionErrors(t *testing.T) {
+	path := writeConfigFile(t, testConfigFile)
+	_, err := LoadFile(path, "office")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown profile "office" (available: broken, home, lab)`)
+
+	path = writeConfigFile(t, "profiles:\n  a:\n    host: https://a\n  b:\n    host: https://b\n")
+	_, err = LoadFile(path, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "select one with -profile")
+
+	path = writeConfigFile(t, "profiles:\n  only:\n    host: https://a\n    api_key: k\n")
+	cfg, err := LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "only", cfg.Profile)
+}
+
+func TestLoadFile_FileErrors(t *testing.T) {
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file")
+
+	_, err = LoadFile(writeConfigFile(t, "profiles: [nope"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse config file")
+
+	_, err = LoadFile(writeConfigFile(t, "default_profile: home\n"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "defines no profiles")
+}
+
+func TestLoadFile_DefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("UNIFI_CONFIG", "")
+
+	// Without a default file only the environment is used.
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "env-key")
+	cfg, err := LoadFile("", "")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Profile)
+
+	_, err = LoadFile("", "lab")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no config file found")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(DefaultPath()), 0o700))
+	require.NoError(t, os.WriteFile(DefaultPath(), []byte(testConfigFile), 0o600))
+	cfg, err = LoadFile("", "lab")
+	require.NoError(t, err)
+	assert.Equal(t, "lab", cfg.Profile)
+	assert.Equal(t, "https://192.168.1.1", cfg.Host)
+}
+
+func TestLoadFile_Controllers(t *testing.T) {
+	path := writeConfigFile(t, "controllers: [home, lab]\n"+testConfigFile)
+
+	cfg, err := LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "home", cfg.Profile)
+	require.Len(t, cfg.Controllers, 1)
+	lab := cfg.Controllers[0]
+	assert.Equal(t, "lab", lab.Profile)
+	assert.Equal(t, "https://10.0.0.1", lab.Host)
+	assert.Equal(t, "default", lab.Site)
+	assert.False(t, lab.VerifySSL)
+	assert.Equal(t, []string{"default"}, lab.AllowedSites)
+
+	// The selected profile is not served twice.
+	cfg, err = LoadFile(path, "lab")
+	require.NoError(t, err)
+	require.Len(t, cfg.Controllers, 1)
+	assert.Equal(t, "home", cfg.Controllers[0].Profile)
+	assert.True(t, cfg.Controllers[0].VerifySSL)
+}
+
+func TestLoadFile_ControllersFromEnvironment(t *testing.T) {
+	path := writeConfigFile(t, testConfigFile)
+
+	t.Setenv("UNIFI_CONTROLLERS", "lab")
+	cfg, err := LoadFile(path, "")
+	require.NoError(t, err)
+	require.Len(t, cfg.Controllers, 1)
+	assert.Equal(t, "lab", cfg.Controllers[0].Profile)
+
+	t.Setenv("UNIFI_CONTROLLERS", "office")
+	_, err = LoadFile(path, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown controller profile "office"`)
+
+	t.Setenv("UNIFI_CONTROLLERS", "broken")
+	_, err = LoadFile(path, "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrMissingCredentials)
+	assert.Contains(t, err.Error(), `profile "broken"`)
+}
+
+func TestLoadFile_ControllersShareHTTPSettings(t *testing.T) {
+	path := writeConfigFile(t, `
+profiles:
+  home:
+    host: https://192.168.1.1
+    api_key: home-key
+    proxy: http://proxy.home:3128
+  lab:
+    host: https://10.0.0.1
+    api_key: lab-key
+  office:
+    host: https://10.1.0.1
+    api_key: office-key
+    proxy: http://proxy.office:3128
+`)
+	t.Setenv("UNIFI_CONTROLLERS", "lab,office")
+	t.Setenv("UNIFI_TIMEOUT", "5s")
+	t.Setenv("UNIFI_RETRIES", "4")
+
+	cfg, err := LoadFile(path, "home")
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.home:3128", cfg.Proxy)
+	require.Len(t, cfg.Controllers, 2)
+	for _, ctrl := range cfg.Controllers {
+		assert.Equal(t, 5*time.Second, ctrl.Timeout, ctrl.Profile)
+		assert.Equal(t, 4, ctrl.Retries, ctrl.Profile)
+		assert.Equal(t, DefaultRetryBackoff, ctrl.RetryBackoff, ctrl.Profile)
+	}
+	assert.Equal(t, "http://proxy.home:3128", cfg.Controllers[0].Proxy)
+	assert.Equal(t, "http://proxy.office:3128", cfg.Controllers[1].Proxy)
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Secret is an API key read from a file or the output of a credential
+// command. Reload reads it again, so a rotated credential is picked up
+// without restarting the server.
+type Secret struct {
+	source string
+	read   func() (string, error)
+
+	mu    sync.RWMutex
+	value string
+}
+
+// newSecret reads the secret once and returns it. source describes where it
+// comes from in error messages.
+func newSecret(source string, read func() (string, error)) (*Secret, error) {
+	s := &Secret{source: source, read: read}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Value returns the secret as last read.
+func (s *Secret) Value() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+// Reload reads the secret again. The previous value is kept if reading fails
+// or yields an empty value.
+func (s *Secret) Reload() error {
+	value, err := s.read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.source, err)
+	}
+	if value == "" {
+		return fmt.Errorf("%s: empty value", s.source)
+	}
+	s.mu.Lock()
+	s.value = value
+	s.mu.Unlock()
+	return nil
+}
+
+// readSecretFile returns the contents of a secret file without surrounding
+// whitespace, so files written with a trailing newline work.
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// runCredentialCommand runs a credential helper through the shell and returns
+// its standard output without surrounding whitespace.
+func runCredentialCommand(command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ReadSecrets reads the API key and password from their files or the
+// credential command. A file takes precedence over a value given directly.
+// The credential command provides the password if a username is set and the
+// API key otherwise, unless that credential is already set.
+//
+// Only the API key can be reloaded: a password is used once, to log in when
+// the client is created.
+func (c *Config) ReadSecrets() error {
+	c.APIKeySecret = nil
+
+	if c.APIKeyFile != "" {
+		path := c.APIKeyFile
+		secret, err := newSecret("api key file "+path, func() (string, error) { return readSecretFile(path) })
+		if err != nil {
+			return err
+		}
+		c.APIKeySecret = secret
+	}
+	if c.PasswordFile != "" {
+		password, err := readSecretFile(c.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("password file %s: %w", c.PasswordFile, err)
+		}
+		c.Password = password
+	}
+
+	if c.CredentialCommand != "" {
+		command := c.CredentialCommand
+		switch {
+		case c.Username != "":
+			if c.Password == "" {
+				password, err := runCredentialCommand(command)
+				if err != nil {
+					return fmt.Errorf("credential command: %w", err)
+				}
+				c.Password = password
+			}
+		case c.APIKeySecret == nil && c.APIKey == "":
+			secret, err := newSecret("credential command", func() (string, error) { return runCredentialCommand(command) })
+			if err != nil {
+				return err
+			}
+			c.APIKeySecret = secret
+		}
+	}
+
+	if c.APIKeySecret != nil {
+		c.APIKey = c.APIKeySecret.Value()
+	}
+	return nil
+}
+
+// ReloadSecrets reads API keys that come from a file or the credential
+// command again, including those of the other controllers. Errors are
+// joined; keys that fail to reload keep their previous value.
+func (c *Config) ReloadSecrets() error {
+	var errs []error
+	for _, cfg := range append([]*Config{c}, c.Controllers...) {
+		if cfg.APIKeySecret == nil {
+			continue
+		}
+		if err := cfg.APIKeySecret.Reload(); err != nil {
+			if cfg.Profile != "" {
+				err = fmt.Errorf("profile %q: %w", cfg.Profile, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
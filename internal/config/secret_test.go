@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecretFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_APIKeyFile(t *testing.T) {
+	path := writeSecretFile(t, "api_key", "file-key\n")
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "")
+	t.Setenv("UNIFI_API_KEY_FILE", path)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "file-key", cfg.APIKey)
+	require.NotNil(t, cfg.APIKeySecret)
+	assert.Equal(t, "file-key", cfg.APIKeySecret.Value())
+
+	// A rotated key is picked up on reload.
+	require.NoError(t, os.WriteFile(path, []byte("rotated-key\n"), 0o600))
+	require.NoError(t, cfg.ReloadSecrets())
+	assert.Equal(t, "rotated-key", cfg.APIKeySecret.Value())
+
+	// A failed reload keeps the previous key.
+	require.NoError(t, os.Remove(path))
+	err = cfg.ReloadSecrets()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api key file "+path)
+	assert.Equal(t, "rotated-key", cfg.APIKeySecret.Value())
+}
+
+func TestLoad_PasswordFile(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "")
+	t.Setenv("UNIFI_USERNAME", "admin")
+	t.Setenv("UNIFI_PASSWORD", "")
+	t.Setenv("UNIFI_PASSWORD_FILE", writeSecretFile(t, "password", "file-password\n"))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.UseUserPass())
+	assert.Equal(t, "file-password", cfg.Password)
+	assert.Nil(t, cfg.APIKeySecret)
+}
+
+func TestLoad_SecretFileErrors(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "env-key")
+	t.Setenv("UNIFI_API_KEY_FILE", writeSecretFile(t, "api_key", "file-key"))
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of UNIFI_API_KEY and UNIFI_API_KEY_FILE may be set")
+
+	t.Setenv("UNIFI_API_KEY", "")
+	t.Setenv("UNIFI_API_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api key file")
+
+	t.Setenv("UNIFI_API_KEY_FILE", writeSecretFile(t, "empty", "\n"))
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty value")
+}
+
+func TestLoad_CredentialCommand(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "")
+	t.Setenv("UNIFI_USERNAME", "")
+	t.Setenv("UNIFI_PASSWORD", "")
+	t.Setenv("UNIFI_CREDENTIAL_COMMAND", "echo command-key")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "command-key", cfg.APIKey)
+	require.NotNil(t, cfg.APIKeySecret)
+
+	// With a username the command provides the password.
+	t.Setenv("UNIFI_USERNAME", "admin")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.APIKey)
+	assert.Equal(t, "command-key", cfg.Password)
+
+	// Credentials given directly take precedence.
+	t.Setenv("UNIFI_PASSWORD", "env-password")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "env-password", cfg.Password)
+}
+
+func TestLoad_CredentialCommandFails(t *testing.T) {
+	t.Setenv("UNIFI_HOST", "https://192.168.1.1")
+	t.Setenv("UNIFI_API_KEY", "")
+	t.Setenv("UNIFI_USERNAME", "")
+	t.Setenv("UNIFI_CREDENTIAL_COMMAND", "echo vault is sealed >&2; exit 3")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "credential command")
+	assert.Contains(t, err.Error(), "vault is sealed")
+}
+
+func TestLoadFile_ProfileSecrets(t *testing.T) {
+	keyFile := writeSecretFile(t, "lab_key", "lab-key\n")
+	path := writeConfigFile(t, `
+default_profile: home
+controllers: [home, lab]
+profiles:
+  home:
+    host: https://192.168.1.1
+    credential_command: echo home-key
+  lab:
+    host: https://10.0.0.1
+    api_key_file: `+keyFile+`
+`)
+
+	cfg, err := LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "home-key", cfg.APIKey)
+	require.Len(t, cfg.Controllers, 1)
+	assert.Equal(t, "lab-key", cfg.Controllers[0].APIKey)
+
+	require.NoError(t, os.Remove(keyFile))
+	err = cfg.ReloadSecrets()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `profile "lab"`)
+}
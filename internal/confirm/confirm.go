@@ -0,0 +1,162 @@
+// Package confirm asks the human behind the MCP client to approve destructive
+// tool calls before they reach the controller. The change is previewed with a
+// dry run and shown to the user through an MCP elicitation request; calls are
+// refused unless the user explicitly accepts.
+package confirm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/claytono/go-unifi-mcp/internal/journal"
+	"github.com/claytono/go-unifi-mcp/internal/redact"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ConfirmField is the boolean the user sets in the elicitation response to
+// approve a change.
+const ConfirmField = "confirm"
+
+// ErrElicitationUnsupported is returned when the client did not declare the
+// elicitation capability.
+var ErrElicitationUnsupported = errors.New("client does not support elicitation")
+
+// Elicitor sends elicitation requests to the client of the current session.
+// *server.MCPServer implements it.
+type Elicitor interface {
+	RequestElicitation(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error)
+}
+
+// Confirmer requires user approval for mutating tools matching its patterns.
+type Confirmer struct {
+	elicitor Elicitor
+	tools    registry.ToolPatterns
+}
+
+// New returns a Confirmer for the tools matching patterns, in the
+// registry.ToolFilter syntax. Read-only tools never require confirmation.
+func New(elicitor Elicitor, patterns []string) (*Confirmer, error) {
+	tools, err := registry.ParseToolPatterns(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid confirmation pattern: %w", err)
+	}
+	return &Confirmer{elicitor: elicitor, tools: tools}, nil
+}
+
+// Requires reports whether calls to the tool need confirmation.
+func (c *Confirmer) Requires(meta generated.ToolMetadata) bool {
+	return !registry.IsReadOnly(meta) && c.tools.Match(meta)
+}
+
+// Middleware previews matching calls with a dry run, asks the user to approve
+// the preview and only then runs the call. Calls that are already dry runs
+// pass straight through since they change nothing, and so do the calls that
+// roll back a failed transaction, see journal.WithRollback.
+func (c *Confirmer) Middleware(_ unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !c.Requires(meta) {
+		return next
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		if dryRun, _ := args[generated.DryRunArg].(bool); dryRun || journal.IsRollback(ctx) {
+			return next(ctx, req)
+		}
+
+		preview := req
+		previewArgs := make(map[string]any, len(args)+1)
+		for k, v := range args {
+			previewArgs[k] = v
+		}
+		previewArgs[generated.DryRunArg] = true
+		preview.Params.Arguments = previewArgs
+
+		plan, err := next(ctx, preview)
+		if err != nil || plan == nil || plan.IsError {
+			// The call would fail anyway; report why without asking.
+			return plan, err
+		}
+
+		if err := c.confirm(ctx, meta, plan); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("tool %s was not run: %v", meta.Name, err)), nil
+		}
+		return next(ctx, req)
+	}
+}
+
+// confirm shows the planned change to the user and returns nil only if they
+// accepted it.
+func (c *Confirmer) confirm(ctx context.Context, meta generated.ToolMetadata, plan *mcp.CallToolResult) error {
+	if !supportsElicitation(ctx) {
+		return fmt.Errorf("confirmation required but %w", ErrElicitationUnsupported)
+	}
+
+	// The prompt is shown to the user and may be logged by the client, so
+	// secrets in the plan are masked whatever the caller may see.
+	plan = redact.MaskResult(plan, redact.SecretsFor(meta.Resource))
+	result, err := c.elicitor.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message:         message(meta, plan),
+			RequestedSchema: requestedSchema,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("confirmation required but could not be requested: %w", err)
+	}
+
+	switch result.Action {
+	case mcp.ElicitationResponseActionAccept:
+		content, _ := result.Content.(map[string]any)
+		if approved, _ := content[ConfirmField].(bool); approved {
+			return nil
+		}
+		return errors.New("the change was not approved")
+	case mcp.ElicitationResponseActionDecline:
+		return errors.New("the user declined the change")
+	default:
+		return errors.New("the user cancelled the confirmation")
+	}
+}
+
+var requestedSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		ConfirmField: map[string]any{
+			"type":        "boolean",
+			"title":       "Apply this change",
+			"description": "Set to true to send the change to the UniFi controller",
+		},
+	},
+	"required": []string{ConfirmField},
+}
+
+// supportsElicitation reports whether the session's client declared the
+// elicitation capability. Without a session nobody can be asked. Sessions that
+// do not expose their capabilities are given the benefit of the doubt;
+// RequestElicitation fails for them if needed.
+func supportsElicitation(ctx context.Context) bool {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return false
+	}
+	withInfo, ok := session.(server.SessionWithClientInfo)
+	if !ok {
+		return true
+	}
+	return withInfo.GetClientCapabilities().Elicitation != nil
+}
+
+func message(meta generated.ToolMetadata, plan *mcp.CallToolResult) string {
+	msg := fmt.Sprintf("Approve %s? This %s of %s will be applied to the UniFi controller.",
+		meta.Name, meta.Category, meta.Resource)
+	for _, content := range plan.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			msg += "\n\nPlanned change:\n" + text.Text
+		}
+	}
+	return msg
+}
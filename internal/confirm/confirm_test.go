@@ -0,0 +1,229 @@
+package confirm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/claytono/go-unifi-mcp/internal/redact"
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var deleteNetwork = generated.ToolMetadata{Name: "delete_network", Category: "delete", Resource: "Network"}
+
+type fakeElicitor struct {
+	result   *mcp.ElicitationResult
+	err      error
+	requests []mcp.ElicitationRequest
+}
+
+func (f *fakeElicitor) RequestElicitation(_ context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	f.requests = append(f.requests, req)
+	return f.result, f.err
+}
+
+func answer(action mcp.ElicitationResponseAction, content any) *mcp.ElicitationResult {
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{Action: action, Content: content}}
+}
+
+// recorder is a tool handler that records the arguments of every call and
+// answers dry runs with a plan.
+type recorder struct {
+	calls []map[string]any
+}
+
+func (r *recorder) handle(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.calls = append(r.calls, req.GetArguments())
+	if dryRun, _ := req.GetArguments()[generated.DryRunArg].(bool); dryRun {
+		return mcp.NewToolResultText(`{"dry_run": true, "action": "delete"}`), nil
+	}
+	return mcp.NewToolResultText(`{"success": true}`), nil
+}
+
+// sessionContext returns a context carrying a client session that declared
+// the elicitation capability if elicitation is true.
+func sessionContext(elicitation bool) context.Context {
+	session := server.NewInProcessSession("test", nil)
+	if elicitation {
+		session.SetClientCapabilities(mcp.ClientCapabilities{Elicitation: &struct{}{}})
+	}
+	s := server.NewMCPServer("test", "1.0")
+	return s.WithContext(context.Background(), session)
+}
+
+func call(t *testing.T, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	return callWithContext(t, sessionContext(true), handler, args)
+}
+
+func callWithContext(t *testing.T, ctx context.Context, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handler(ctx, req)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	return result
+}
+
+func TestRequires(t *testing.T) {
+	c, err := New(&fakeElicitor{}, []string{"category:delete", "resource:Firewall*"})
+	require.NoError(t, err)
+
+	assert.True(t, c.Requires(deleteNetwork))
+	assert.True(t, c.Requires(generated.ToolMetadata{Name: "update_firewall_rule", Category: "update", Resource: "FirewallRule"}))
+	assert.False(t, c.Requires(generated.ToolMetadata{Name: "list_firewall_rule", Category: "list", Resource: "FirewallRule"}))
+	assert.False(t, c.Requires(generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"}))
+
+	none, err := New(&fakeElicitor{}, nil)
+	require.NoError(t, err)
+	assert.False(t, none.Requires(deleteNetwork))
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	_, err := New(&fakeElicitor{}, []string{"owner:me"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid confirmation pattern")
+}
+
+func TestMiddleware_Accepted(t *testing.T) {
+	elicitor := &fakeElicitor{result: answer(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": true})}
+	c, err := New(elicitor, []string{"category:delete"})
+	require.NoError(t, err)
+	rec := &recorder{}
+
+	result := call(t, c.Middleware(nil, deleteNetwork, rec.handle), map[string]any{"id": "abc"})
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "success")
+
+	// The change is previewed, shown to the user, then applied.
+	require.Len(t, rec.calls, 2)
+	assert.Equal(t, map[string]any{"id": "abc", "dry_run": true}, rec.calls[0])
+	assert.Equal(t, map[string]any{"id": "abc"}, rec.calls[1])
+	require.Len(t, elicitor.requests, 1)
+	assert.Contains(t, elicitor.requests[0].Params.Message, "delete_network")
+	assert.Contains(t, elicitor.requests[0].Params.Message, `"action": "delete"`)
+}
+
+func TestMiddleware_MasksSecretsInPrompt(t *testing.T) {
+	elicitor := &fakeElicitor{result: answer(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": true})}
+	c, err := New(elicitor, []string{"resource:WLAN"})
+	require.NoError(t, err)
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").
+		Return(&unifi.WLAN{ID: "w1", Name: "Home", XPassphrase: "hunter22"}, nil).Twice()
+	client.On("UpdateWLAN", mock.Anything, "default", mock.Anything).
+		Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+	updateWLAN := generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"}
+	update := generated.GenericUpdate(client, "WLAN", func() any { return &unifi.WLAN{} }, false)
+
+	result := call(t, c.Middleware(client, updateWLAN, update), map[string]any{
+		"site": "default", "id": "w1", "x_passphrase": "correct horse",
+	})
+	require.False(t, result.IsError)
+
+	// The prompt says the passphrase changes but shows neither value.
+	require.Len(t, elicitor.requests, 1)
+	msg := elicitor.requests[0].Params.Message
+	assert.Contains(t, msg, `"field": "x_passphrase"`)
+	assert.Contains(t, msg, redact.Marker)
+	assert.NotContains(t, msg, "hunter22")
+	assert.NotContains(t, msg, "correct horse")
+}
+
+func TestMiddleware_Refused(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *mcp.ElicitationResult
+		err      error
+		expected string
+	}{
+		{"declined", answer(mcp.ElicitationResponseActionDecline, nil), nil, "declined"},
+		{"cancelled", answer(mcp.ElicitationResponseActionCancel, nil), nil, "cancelled"},
+		{"not approved", answer(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": false}), nil, "not approved"},
+		{"request failed", nil, errors.New("boom"), "could not be requested: boom"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := New(&fakeElicitor{result: tc.result, err: tc.err}, []string{"category:delete"})
+			require.NoError(t, err)
+			rec := &recorder{}
+
+			result := call(t, c.Middleware(nil, deleteNetwork, rec.handle), map[string]any{"id": "abc"})
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "tool delete_network was not run")
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.expected)
+			// Only the preview ran.
+			assert.Len(t, rec.calls, 1)
+		})
+	}
+}
+
+func TestMiddleware_ElicitationUnsupported(t *testing.T) {
+	contexts := map[string]context.Context{
+		"no capability": sessionContext(false),
+		"no session":    context.Background(),
+	}
+
+	for name, ctx := range contexts {
+		t.Run(name, func(t *testing.T) {
+			elicitor := &fakeElicitor{}
+			c, err := New(elicitor, []string{"category:delete"})
+			require.NoError(t, err)
+			rec := &recorder{}
+
+			result := callWithContext(t, ctx, c.Middleware(nil, deleteNetwork, rec.handle), map[string]any{"id": "abc"})
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "does not support elicitation")
+			assert.Len(t, rec.calls, 1)
+			assert.Empty(t, elicitor.requests)
+		})
+	}
+}
+
+func TestMiddleware_DryRunSkipsConfirmation(t *testing.T) {
+	elicitor := &fakeElicitor{}
+	c, err := New(elicitor, []string{"category:delete"})
+	require.NoError(t, err)
+	rec := &recorder{}
+
+	result := call(t, c.Middleware(nil, deleteNetwork, rec.handle), map[string]any{"id": "abc", "dry_run": true})
+	assert.False(t, result.IsError)
+	assert.Len(t, rec.calls, 1)
+	assert.Empty(t, elicitor.requests)
+}
+
+func TestMiddleware_PreviewErrorSkipsConfirmation(t *testing.T) {
+	elicitor := &fakeElicitor{}
+	c, err := New(elicitor, []string{"category:delete"})
+	require.NoError(t, err)
+	failing := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("network not found"), nil
+	}
+
+	result := call(t, c.Middleware(nil, deleteNetwork, failing), map[string]any{"id": "abc"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "network not found")
+	assert.Empty(t, elicitor.requests)
+}
+
+func TestMiddleware_UnmatchedToolPassesThrough(t *testing.T) {
+	elicitor := &fakeElicitor{}
+	c, err := New(elicitor, []string{"category:delete"})
+	require.NoError(t, err)
+	rec := &recorder{}
+
+	meta := generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"}
+	result := call(t, c.Middleware(nil, meta, rec.handle), map[string]any{"id": "abc"})
+	assert.False(t, result.IsError)
+	assert.Equal(t, []map[string]any{{"id": "abc"}}, rec.calls)
+	assert.Empty(t, elicitor.requests)
+}
@@ -0,0 +1,221 @@
+// Package controllers lets one server talk to several UniFi controllers.
+// Every tool accepts an optional controller argument naming the controller a
+// call is sent to; calls without it go to the default controller.
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Arg is the tool argument that selects a controller.
+const Arg = "controller"
+
+// DefaultName names the controller of a server configured without profiles.
+const DefaultName = "default"
+
+// Controller is a named UniFi controller.
+type Controller struct {
+	Name   string
+	Client unifi.Client
+}
+
+// Set is the controllers a server talks to. The first is the default.
+type Set struct {
+	controllers []Controller
+	byName      map[string]unifi.Client
+}
+
+// NewSet returns a set of controllers, the first being the default. Names
+// must be unique and non-empty.
+func NewSet(controllers []Controller) (*Set, error) {
+	if len(controllers) == 0 {
+		return nil, errors.New("at least one controller is required")
+	}
+	s := &Set{controllers: controllers, byName: make(map[string]unifi.Client, len(controllers))}
+	for _, c := range controllers {
+		if c.Name == "" {
+			return nil, errors.New("controller name is required")
+		}
+		if c.Client == nil {
+			return nil, fmt.Errorf("controller %q has no client", c.Name)
+		}
+		if _, dup := s.byName[c.Name]; dup {
+			return nil, fmt.Errorf("duplicate controller %q", c.Name)
+		}
+		s.byName[c.Name] = c.Client
+	}
+	return s, nil
+}
+
+// Names returns the controller names, default first.
+func (s *Set) Names() []string {
+	names := make([]string, len(s.controllers))
+	for i, c := range s.controllers {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Default returns the default controller.
+func (s *Set) Default() Controller {
+	return s.controllers[0]
+}
+
+// Toolset returns a toolset whose tools take a controller argument and whose
+// handlers route each call to that controller. The client the handlers are
+// built with is ignored. Middlewares applied to t before run per controller,
+// with that controller's client.
+func (s *Set) Toolset(t registry.Toolset) registry.Toolset {
+	tools := make([]generated.ToolMetadata, len(t.Tools))
+	for i, meta := range t.Tools {
+		meta.InputSchema = registry.AddProperty(meta.InputSchema, Arg, ArgSchema(s.Names()))
+		tools[i] = meta
+	}
+
+	handlers := make(map[string]generated.HandlerFunc, len(t.Handlers))
+	for name, factory := range t.Handlers {
+		handlers[name] = s.dispatch(factory)
+	}
+
+	return registry.Toolset{Tools: tools, Handlers: handlers, Controllers: s.Names()}
+}
+
+// ArgSchema is the JSON schema of the controller argument.
+func ArgSchema(names []string) map[string]any {
+	enum := make([]any, len(names))
+	for i, name := range names {
+		enum[i] = name
+	}
+	return map[string]any{
+		"type":        "string",
+		"enum":        enum,
+		"description": fmt.Sprintf("UniFi controller to use (default: %s)", names[0]),
+	}
+}
+
+func (s *Set) dispatch(factory generated.HandlerFunc) generated.HandlerFunc {
+	return func(_ unifi.Client) server.ToolHandlerFunc {
+		handlers := make(map[string]server.ToolHandlerFunc, len(s.controllers))
+		for _, c := range s.controllers {
+			handlers[c.Name] = factory(c.Client)
+		}
+
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.GetArguments()
+			name, _ := args[Arg].(string)
+			if name == "" {
+				name = s.Default().Name
+			}
+			handler, ok := handlers[name]
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("unknown controller %q (available: %s)",
+					name, strings.Join(s.Names(), ", "))), nil
+			}
+
+			newArgs := make(map[string]any, len(args))
+			for k, v := range args {
+				if k != Arg {
+					newArgs[k] = v
+				}
+			}
+			req.Params.Arguments = newArgs
+
+			return handler(WithName(ctx, name), req)
+		}
+	}
+}
+
+type nameKey struct{}
+
+// WithName records the controller a call is routed to.
+func WithName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, nameKey{}, name)
+}
+
+// NameFromContext returns the controller a call was routed to, or "" when
+// the server has a single controller.
+func NameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(nameKey{}).(string)
+	return name
+}
+
+// Status describes a controller as reported by list_controllers.
+type Status struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Default   bool   `json:"default"`
+	Reachable bool   `json:"reachable"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ListControllersHandler returns a handler that reports each controller's
+// name, base URL, version and whether it currently answers requests. The
+// controllers are probed at once, so an unreachable one does not hold up
+// the others. The client's probe takes no context and is bounded by the
+// client's own timeout; when ctx is done the handler stops waiting and
+// reports the controllers that have not answered as failed.
+func (s *Set) ListControllersHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		statuses := make([]Status, len(s.controllers))
+		probes := make([]chan Status, len(s.controllers))
+		for i, c := range s.controllers {
+			statuses[i] = Status{Name: c.Name, URL: c.Client.BaseURL(), Default: i == 0}
+			probes[i] = make(chan Status, 1)
+			go func(status Status) {
+				probes[i] <- probe(c.Client, status)
+			}(statuses[i])
+		}
+		for i := range statuses {
+			select {
+			case statuses[i] = <-probes[i]:
+				continue
+			case <-ctx.Done():
+			}
+			// Probes that answered in time count even after ctx is done.
+			select {
+			case statuses[i] = <-probes[i]:
+			default:
+				statuses[i].Error = ctx.Err().Error()
+			}
+		}
+
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError("failed to marshal controllers: " + err.Error()), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// probe completes a controller's status with its system information.
+func probe(client unifi.Client, status Status) Status {
+	info, err := client.GetSystemInformation()
+	switch {
+	case err != nil:
+		status.Error = err.Error()
+	case info == nil:
+		status.Error = "no system information returned"
+	default:
+		status.Reachable = true
+		status.Version = info.Version
+	}
+	return status
+}
+
+// Register adds the list_controllers tool to the server.
+func (s *Set) Register(srv *server.MCPServer) {
+	srv.AddTool(mcp.NewTool("list_controllers",
+		mcp.WithDescription("Lists the UniFi controllers this server can talk to, with their URL, version and reachability. Pass a name as the controller argument of any tool."),
+	), s.ListControllersHandler())
+}
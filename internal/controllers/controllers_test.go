@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSet_Errors(t *testing.T) {
+	client := servermocks.NewClient(t)
+
+	_, err := NewSet(nil)
+	assert.EqualError(t, err, "at least one controller is required")
+
+	_, err = NewSet([]Controller{{Client: client}})
+	assert.EqualError(t, err, "controller name is required")
+
+	_, err = NewSet([]Controller{{Name: "home"}})
+	assert.EqualError(t, err, `controller "home" has no client`)
+
+	_, err = NewSet([]Controller{{Name: "home", Client: client}, {Name: "home", Client: client}})
+	assert.EqualError(t, err, `duplicate controller "home"`)
+}
+
+func TestSet_Toolset(t *testing.T) {
+	home := servermocks.NewClient(t)
+	lab := servermocks.NewClient(t)
+	set, err := NewSet([]Controller{{Name: "home", Client: home}, {Name: "lab", Client: lab}})
+	require.NoError(t, err)
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"site": map[string]any{"type": "string"}},
+	}
+	type call struct {
+		client     unifi.Client
+		controller string
+		args       map[string]any
+	}
+	var calls []call
+	tools := set.Toolset(registry.Toolset{
+		Tools: []generated.ToolMetadata{{Name: "list_wlan", Category: "list", Resource: "WLAN", InputSchema: schema}},
+		Handlers: map[string]generated.HandlerFunc{
+			"list_wlan": func(client unifi.Client) server.ToolHandlerFunc {
+				return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					calls = append(calls, call{client, NameFromContext(ctx), req.GetArguments()})
+					return mcp.NewToolResultText("ok"), nil
+				}
+			},
+		},
+	})
+
+	assert.Equal(t, []string{"home", "lab"}, tools.Controllers)
+	require.Len(t, tools.Tools, 1)
+	props := tools.Tools[0].InputSchema["properties"].(map[string]any)
+	assert.Contains(t, props, "site")
+	assert.Equal(t, []any{"home", "lab"}, props[Arg].(map[string]any)["enum"])
+	// The original schema is left untouched.
+	assert.NotContains(t, schema["properties"], Arg)
+
+	handler := tools.Handlers["list_wlan"](nil)
+	for _, args := range []map[string]any{{"site": "default"}, {"site": "default", Arg: "lab"}} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+	}
+	require.Len(t, calls, 2)
+	assert.Same(t, home, calls[0].client)
+	assert.Equal(t, "home", calls[0].controller)
+	assert.Same(t, lab, calls[1].client)
+	assert.Equal(t, "lab", calls[1].controller)
+	assert.Equal(t, map[string]any{"site": "default"}, calls[1].args)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{Arg: "office"}
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, `unknown controller "office" (available: home, lab)`, result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestSet_ListControllersHandler(t *testing.T) {
+	home := servermocks.NewClient(t)
+	home.On("BaseURL").Return("https://192.168.1.1")
+	home.On("GetSystemInformation").Return(&unifi.SysInfo{Version: "9.0.114"}, nil)
+	lab := servermocks.NewClient(t)
+	lab.On("BaseURL").Return("https://10.0.0.1")
+	lab.On("GetSystemInformation").Return(nil, errors.New("connection refused"))
+
+	set, err := NewSet([]Controller{{Name: "home", Client: home}, {Name: "lab", Client: lab}})
+	require.NoError(t, err)
+
+	result, err := set.ListControllersHandler()(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var statuses []Status
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &statuses))
+	assert.Equal(t, []Status{
+		{Name: "home", URL: "https://192.168.1.1", Default: true, Reachable: true, Version: "9.0.114"},
+		{Name: "lab", URL: "https://10.0.0.1", Error: "connection refused"},
+	}, statuses)
+}
+
+func TestSet_ListControllersHandler_StopsOnCancel(t *testing.T) {
+	home := servermocks.NewClient(t)
+	home.On("BaseURL").Return("https://192.168.1.1")
+	home.On("GetSystemInformation").Return(&unifi.SysInfo{Version: "9.0.114"}, nil)
+	// The lab controller hangs until the test ends.
+	release := make(chan time.Time)
+	defer close(release)
+	lab := servermocks.NewClient(t)
+	lab.On("BaseURL").Return("https://10.0.0.1")
+	lab.On("GetSystemInformation").WaitUntil(release).Return(nil, errors.New("timeout"))
+
+	set, err := NewSet([]Controller{{Name: "lab", Client: lab}, {Name: "home", Client: home}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := set.ListControllersHandler()(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// The hanging controller does not hold up the answer of the other.
+	var statuses []Status
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &statuses))
+	assert.Equal(t, []Status{
+		{Name: "lab", URL: "https://10.0.0.1", Default: true, Error: context.DeadlineExceeded.Error()},
+		{Name: "home", URL: "https://192.168.1.1", Reachable: true, Version: "9.0.114"},
+	}, statuses)
+}
@@ -0,0 +1,380 @@
+// Package journal remembers the recent changes made through the server so
+// they can be listed and undone. Each entry keeps the full state of the
+// resource before and after the change. The journal lives in memory only and
+// is lost when the server stops.
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrDrift is returned by Entry.CheckDrift when the resource changed again
+// after the journaled change.
+var ErrDrift = errors.New("resource changed since")
+
+// Entry is one successful create, update or delete.
+type Entry struct {
+	ID         int64          `json:"id"`
+	Time       time.Time      `json:"time"`
+	Session    string         `json:"session,omitempty"`
+	Tool       string         `json:"tool"`
+	Category   string         `json:"category"`
+	Resource   string         `json:"resource"`
+	IsSetting  bool           `json:"-"`
+	Controller string         `json:"controller,omitempty"` // empty with a single controller
+	Client     unifi.Client   `json:"-"`                    // client the change was made with
+	Site       string         `json:"site"`
+	ResourceID string         `json:"resource_id,omitempty"` // empty for settings
+	Before     map[string]any `json:"before,omitempty"`      // nil for creates
+	After      map[string]any `json:"after,omitempty"`       // nil for deletes
+	UndoOf     int64          `json:"undo_of,omitempty"`     // change this entry reversed
+	UndoneBy   int64          `json:"undone_by,omitempty"`   // change that reversed this entry
+}
+
+// Journal keeps the most recent changes, oldest first.
+type Journal struct {
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	nextID  int64
+	entries []Entry
+}
+
+// New returns a journal that keeps the last size changes, at least one.
+func New(size int) *Journal {
+	if size < 1 {
+		size = 1
+	}
+	return &Journal{size: size, now: time.Now, nextID: 1}
+}
+
+// Record adds an entry, assigning its ID and time, and drops the oldest
+// entry when the journal is full. If the entry reverses another one, that
+// entry is marked as undone.
+func (j *Journal) Record(e Entry) Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e.ID = j.nextID
+	j.nextID++
+	e.Time = j.now().UTC()
+
+	if e.UndoOf != 0 {
+		if i := j.index(e.UndoOf); i >= 0 {
+			j.entries[i].UndoneBy = e.ID
+		}
+	}
+
+	j.entries = append(j.entries, e)
+	if len(j.entries) > j.size {
+		j.entries = j.entries[len(j.entries)-j.size:]
+	}
+	return e
+}
+
+// List returns the journaled changes, newest first.
+func (j *Journal) List() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	out := make([]Entry, len(j.entries))
+	for i, e := range j.entries {
+		out[len(j.entries)-1-i] = e
+	}
+	return out
+}
+
+// Get returns the entry with the given ID, if it is still journaled.
+func (j *Journal) Get(id int64) (Entry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if i := j.index(id); i >= 0 {
+		return j.entries[i], true
+	}
+	return Entry{}, false
+}
+
+// Latest returns the most recent change that has not been undone and is not
+// itself an undo, so repeated undos walk back through the history.
+func (j *Journal) Latest() (Entry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		if e := j.entries[i]; e.UndoneBy == 0 && e.UndoOf == 0 {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (j *Journal) index(id int64) int {
+	for i, e := range j.entries {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+type undoKey struct{}
+
+// WithUndo marks calls made with ctx as reversing the entry with the given
+// ID, so the journal links the two when the call succeeds.
+func WithUndo(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, undoKey{}, id)
+}
+
+func undoFromContext(ctx context.Context) int64 {
+	id, _ := ctx.Value(undoKey{}).(int64)
+	return id
+}
+
+type rollbackKey struct{}
+
+// WithRollback marks calls made with ctx as compensating for a failed
+// transaction. They reverse changes that were already approved, so they are
+// not confirmed again.
+func WithRollback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rollbackKey{}, true)
+}
+
+// IsRollback reports whether ctx was marked by WithRollback.
+func IsRollback(ctx context.Context) bool {
+	rollback, _ := ctx.Value(rollbackKey{}).(bool)
+	return rollback
+}
+
+// Transaction collects the changes made by calls with its context, see
+// WithTransaction, so they can be reversed together if a later call fails.
+type Transaction struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Changes returns the changes made in the transaction, oldest first.
+func (t *Transaction) Changes() []Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Entry(nil), t.entries...)
+}
+
+func (t *Transaction) add(e Entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, e)
+}
+
+type transactionKey struct{}
+
+// WithTransaction records the changes made by calls with ctx in t as well as
+// in the journal. A nil t stops recording, for calls that reverse the
+// transaction.
+func WithTransaction(ctx context.Context, t *Transaction) context.Context {
+	return context.WithValue(ctx, transactionKey{}, t)
+}
+
+func transactionFromContext(ctx context.Context) *Transaction {
+	t, _ := ctx.Value(transactionKey{}).(*Transaction)
+	return t
+}
+
+// Middleware journals every successful call of a mutating tool. The state of
+// the resource before the call is the snapshot the call's context carries, or
+// is fetched and passed on to the call; the state after it is taken from the
+// call's result. Dry runs and failed calls are not journaled.
+//
+// j may be nil, in which case only calls in a transaction are recorded.
+func (j *Journal) Middleware(client unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if registry.IsReadOnly(meta) {
+		return next
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		tx := transactionFromContext(ctx)
+		if dryRun, _ := args[generated.DryRunArg].(bool); dryRun || (j == nil && tx == nil) {
+			return next(ctx, req)
+		}
+
+		site, _ := args["site"].(string)
+		id, _ := args["id"].(string)
+		var before map[string]any
+		if meta.Category != "create" {
+			// Without a snapshot the change is still listed but cannot be
+			// undone.
+			var current any
+			var err error
+			if ctx, current, err = generated.Snapshot(ctx, client, meta.Resource, site, id); err == nil {
+				before, _ = toMap(current)
+			}
+		}
+
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		e := Entry{
+			Tool:       meta.Name,
+			Category:   meta.Category,
+			Resource:   meta.Resource,
+			IsSetting:  meta.IsSetting,
+			Controller: controllers.NameFromContext(ctx),
+			Client:     client,
+			Site:       site,
+			ResourceID: id,
+			Before:     before,
+			UndoOf:     undoFromContext(ctx),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			e.Session = session.SessionID()
+		}
+		if meta.Category != "delete" {
+			e.After = resultObject(result)
+			if e.ResourceID == "" && !meta.IsSetting {
+				e.ResourceID, _ = e.After["_id"].(string)
+			}
+		}
+		if j != nil {
+			e = j.Record(e)
+		}
+		if tx != nil {
+			tx.add(e)
+		}
+		return result, err
+	}
+}
+
+// Inverse returns the tool and arguments that reverse the change: an update
+// is reverted to the previous object, a created resource is deleted and a
+// deleted resource is recreated from its snapshot. Recreated resources get a
+// new ID from the controller. The arguments name the controller the change
+// was made on, if any.
+func (e Entry) Inverse() (string, map[string]any, error) {
+	tool, args, err := e.inverse()
+	if err == nil && e.Controller != "" {
+		args[controllers.Arg] = e.Controller
+	}
+	return tool, args, err
+}
+
+func (e Entry) inverse() (string, map[string]any, error) {
+	base := strings.TrimPrefix(e.Tool, e.Category+"_")
+	switch e.Category {
+	case "create":
+		if e.ResourceID == "" {
+			return "", nil, fmt.Errorf("change %d cannot be undone: the ID of the created %s is unknown", e.ID, e.Resource)
+		}
+		return "delete_" + base, map[string]any{"site": e.Site, "id": e.ResourceID}, nil
+	case "update":
+		if e.Before == nil {
+			return "", nil, fmt.Errorf("change %d cannot be undone: the previous %s was not recorded", e.ID, e.Resource)
+		}
+		args := make(map[string]any, len(e.Before)+len(e.After)+2)
+		// Fields the update added are cleared; the handler merges the
+		// arguments onto the current object.
+		for k := range e.After {
+			args[k] = nil
+		}
+		for k, v := range e.Before {
+			args[k] = v
+		}
+		args["site"] = e.Site
+		if !e.IsSetting {
+			args["id"] = e.ResourceID
+		}
+		return "update_" + base, args, nil
+	case "delete":
+		if e.Before == nil {
+			return "", nil, fmt.Errorf("change %d cannot be undone: the deleted %s was not recorded", e.ID, e.Resource)
+		}
+		args := make(map[string]any, len(e.Before)+1)
+		for k, v := range e.Before {
+			if k == "_id" {
+				continue
+			}
+			args[k] = v
+		}
+		args["site"] = e.Site
+		return "create_" + base, args, nil
+	default:
+		return "", nil, fmt.Errorf("change %d cannot be undone: unknown category %s", e.ID, e.Category)
+	}
+}
+
+// CheckDrift compares the resource on the controller with the state the
+// change left it in and returns an error wrapping ErrDrift if they differ.
+func (e Entry) CheckDrift(ctx context.Context, client unifi.Client) error {
+	current, err := generated.FetchResource(ctx, client, e.Resource, e.Site, e.ResourceID)
+	if e.Category == "delete" {
+		if err == nil && current != nil {
+			return fmt.Errorf("%w change %d: the deleted %s exists again", ErrDrift, e.ID, e.Resource)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w change %d: %s could not be fetched: %v", ErrDrift, e.ID, e.Resource, err)
+	}
+	if current == nil {
+		return fmt.Errorf("%w change %d: the %s no longer exists", ErrDrift, e.ID, e.Resource)
+	}
+	if e.After == nil {
+		return nil
+	}
+	currentMap, err := toMap(current)
+	if err != nil {
+		return fmt.Errorf("failed to parse current %s: %w", e.Resource, err)
+	}
+	if changes := generated.DiffFields(e.After, currentMap); len(changes) > 0 {
+		fields := make([]string, len(changes))
+		for i, c := range changes {
+			fields[i] = c.Field
+		}
+		return fmt.Errorf("%w change %d: fields changed: %s", ErrDrift, e.ID, strings.Join(fields, ", "))
+	}
+	return nil
+}
+
+// resultObject decodes the object a create or update returned.
+func resultObject(result *mcp.CallToolResult) map[string]any {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			var obj map[string]any
+			if json.Unmarshal([]byte(text.Text), &obj) == nil {
+				return obj
+			}
+		}
+	}
+	return nil
+}
+
+func toMap(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,286 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	updateWLAN = generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"}
+	createWLAN = generated.ToolMetadata{Name: "create_wlan", Category: "create", Resource: "WLAN"}
+	deleteWLAN = generated.ToolMetadata{Name: "delete_wlan", Category: "delete", Resource: "WLAN"}
+)
+
+func call(t *testing.T, ctx context.Context, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handler(ctx, req)
+	require.NoError(t, err)
+	return result
+}
+
+func textResult(text string) server.ToolHandlerFunc {
+	return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+func TestJournal_KeepsMostRecent(t *testing.T) {
+	j := New(2)
+	for _, tool := range []string{"a", "b", "c"} {
+		j.Record(Entry{Tool: tool})
+	}
+
+	entries := j.List()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "c", entries[0].Tool)
+	assert.Equal(t, int64(3), entries[0].ID)
+	assert.Equal(t, "b", entries[1].Tool)
+
+	_, ok := j.Get(1)
+	assert.False(t, ok)
+	e, ok := j.Get(2)
+	require.True(t, ok)
+	assert.Equal(t, "b", e.Tool)
+}
+
+func TestJournal_LatestSkipsUndos(t *testing.T) {
+	j := New(10)
+	_, ok := j.Latest()
+	assert.False(t, ok)
+
+	first := j.Record(Entry{Tool: "update_wlan"})
+	second := j.Record(Entry{Tool: "update_network"})
+	undo := j.Record(Entry{Tool: "update_network", UndoOf: second.ID})
+
+	e, ok := j.Get(second.ID)
+	require.True(t, ok)
+	assert.Equal(t, undo.ID, e.UndoneBy)
+
+	latest, ok := j.Latest()
+	require.True(t, ok)
+	assert.Equal(t, first.ID, latest.ID)
+}
+
+func TestMiddleware_RecordsUpdate(t *testing.T) {
+	j := New(10)
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").
+		Return(&unifi.WLAN{ID: "w1", Name: "Home", XPassphrase: "old-passphrase"}, nil).Once()
+
+	next := textResult(`{"_id": "w1", "name": "Home 2", "x_passphrase": "new-passphrase"}`)
+	ctx := controllers.WithName(WithUndo(context.Background(), 7), "lab")
+	call(t, ctx, j.Middleware(client, updateWLAN, next), map[string]any{
+		"site": "default", "id": "w1", "name": "Home 2",
+	})
+
+	entries := j.List()
+	require.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, "update_wlan", e.Tool)
+	assert.Equal(t, "WLAN", e.Resource)
+	assert.Equal(t, "default", e.Site)
+	assert.Equal(t, "w1", e.ResourceID)
+	assert.Equal(t, int64(7), e.UndoOf)
+	assert.Equal(t, "lab", e.Controller)
+	assert.Same(t, client, e.Client)
+	assert.False(t, e.Time.IsZero())
+	// Secrets are kept so the change can be reverted.
+	assert.Equal(t, "old-passphrase", e.Before["x_passphrase"])
+	assert.Equal(t, "Home 2", e.After["name"])
+}
+
+func TestMiddleware_SharesSnapshot(t *testing.T) {
+	j := New(10)
+	client := servermocks.NewClient(t)
+	// An outer layer has taken the snapshot; neither the journal nor the
+	// update read the WLAN again.
+	client.On("GetWLAN", mock.Anything, "default", "w1").
+		Return(&unifi.WLAN{ID: "w1", Name: "Home", Enabled: true}, nil).Once()
+	client.On("UpdateWLAN", mock.Anything, "default", mock.MatchedBy(func(w *unifi.WLAN) bool {
+		return w.Name == "Home 2" && w.Enabled
+	})).Return(&unifi.WLAN{ID: "w1", Name: "Home 2", Enabled: true}, nil).Once()
+
+	ctx, _, err := generated.Snapshot(context.Background(), client, "WLAN", "default", "w1")
+	require.NoError(t, err)
+	update := generated.GenericUpdate(client, "WLAN", func() any { return &unifi.WLAN{} }, false)
+	result := call(t, ctx, j.Middleware(client, updateWLAN, update), map[string]any{
+		"site": "default", "id": "w1", "name": "Home 2",
+	})
+	require.False(t, result.IsError)
+
+	entries := j.List()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Home", entries[0].Before["name"])
+	assert.Equal(t, "Home 2", entries[0].After["name"])
+}
+
+func TestMiddleware_RecordsCreatedID(t *testing.T) {
+	j := New(10)
+	client := servermocks.NewClient(t)
+
+	call(t, context.Background(), j.Middleware(client, createWLAN, textResult(`{"_id": "w2", "name": "Guest"}`)),
+		map[string]any{"site": "default", "name": "Guest"})
+
+	entries := j.List()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "w2", entries[0].ResourceID)
+	assert.Nil(t, entries[0].Before)
+}
+
+func TestMiddleware_SkipsReadsDryRunsAndFailures(t *testing.T) {
+	j := New(10)
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "gone").Return(nil, errors.New("not found")).Once()
+	failed := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("not found"), nil
+	}
+
+	list := generated.ToolMetadata{Name: "list_wlan", Category: "list", Resource: "WLAN"}
+	call(t, context.Background(), j.Middleware(client, list, textResult(`[]`)), map[string]any{"site": "default"})
+	call(t, context.Background(), j.Middleware(client, deleteWLAN, textResult(`{}`)), map[string]any{"site": "default", "id": "w1", "dry_run": true})
+	call(t, context.Background(), j.Middleware(client, deleteWLAN, failed), map[string]any{"site": "default", "id": "gone"})
+
+	assert.Empty(t, j.List())
+}
+
+func TestMiddleware_Transaction(t *testing.T) {
+	j := New(10)
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+
+	tx := &Transaction{}
+	ctx := WithTransaction(context.Background(), tx)
+	call(t, ctx, j.Middleware(client, createWLAN, textResult(`{"_id": "w2", "name": "Guest"}`)),
+		map[string]any{"site": "default", "name": "Guest"})
+	call(t, ctx, j.Middleware(client, updateWLAN, textResult(`{"_id": "w1", "name": "Home 2"}`)),
+		map[string]any{"site": "default", "id": "w1", "name": "Home 2"})
+
+	changes := tx.Changes()
+	require.Len(t, changes, 2)
+	assert.Equal(t, "w2", changes[0].ResourceID)
+	assert.Equal(t, "Home", changes[1].Before["name"])
+	// The journal records the same entries, with the same IDs.
+	assert.Equal(t, j.List()[0].ID, changes[1].ID)
+
+	// Calls reversing the transaction are not added to it.
+	call(t, WithTransaction(ctx, nil), j.Middleware(client, createWLAN, textResult(`{"_id": "w3"}`)),
+		map[string]any{"site": "default", "name": "Other"})
+	assert.Len(t, tx.Changes(), 2)
+}
+
+func TestMiddleware_NilJournal(t *testing.T) {
+	var j *Journal
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+	next := textResult(`{"_id": "w1", "name": "Home 2"}`)
+	args := map[string]any{"site": "default", "id": "w1", "name": "Home 2"}
+
+	// Without a transaction nothing is fetched or recorded.
+	call(t, context.Background(), j.Middleware(client, updateWLAN, next), args)
+
+	tx := &Transaction{}
+	call(t, WithTransaction(context.Background(), tx), j.Middleware(client, updateWLAN, next), args)
+	require.Len(t, tx.Changes(), 1)
+	assert.Zero(t, tx.Changes()[0].ID)
+}
+
+func TestEntry_Inverse(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		wantTool string
+		wantArgs map[string]any
+		wantErr  string
+	}{
+		{
+			name:     "create deletes",
+			entry:    Entry{ID: 1, Tool: "create_wlan", Category: "create", Resource: "WLAN", Site: "default", ResourceID: "w2"},
+			wantTool: "delete_wlan",
+			wantArgs: map[string]any{"site": "default", "id": "w2"},
+		},
+		{
+			name: "update restores and clears added fields",
+			entry: Entry{ID: 2, Tool: "update_wlan", Category: "update", Resource: "WLAN", Site: "default", ResourceID: "w1",
+				Before: map[string]any{"_id": "w1", "name": "Home"},
+				After:  map[string]any{"_id": "w1", "name": "Home 2", "vlan": "10"}},
+			wantTool: "update_wlan",
+			wantArgs: map[string]any{"site": "default", "id": "w1", "_id": "w1", "name": "Home", "vlan": nil},
+		},
+		{
+			name: "setting update has no id",
+			entry: Entry{ID: 3, Tool: "update_setting_mgmt", Category: "update", Resource: "SettingMgmt", IsSetting: true, Site: "default",
+				Before: map[string]any{"led_enabled": true}},
+			wantTool: "update_setting_mgmt",
+			wantArgs: map[string]any{"site": "default", "led_enabled": true},
+		},
+		{
+			name: "delete recreates without id",
+			entry: Entry{ID: 4, Tool: "delete_wlan", Category: "delete", Resource: "WLAN", Site: "default", ResourceID: "w1",
+				Before: map[string]any{"_id": "w1", "name": "Home"}},
+			wantTool: "create_wlan",
+			wantArgs: map[string]any{"site": "default", "name": "Home"},
+		},
+		{
+			name:     "controller is kept",
+			entry:    Entry{ID: 6, Tool: "create_wlan", Category: "create", Resource: "WLAN", Controller: "lab", Site: "default", ResourceID: "w2"},
+			wantTool: "delete_wlan",
+			wantArgs: map[string]any{"site": "default", "id": "w2", "controller": "lab"},
+		},
+		{
+			name:    "delete without snapshot",
+			entry:   Entry{ID: 5, Tool: "delete_wlan", Category: "delete", Resource: "WLAN", ResourceID: "w1"},
+			wantErr: "change 5 cannot be undone: the deleted WLAN was not recorded",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tool, args, err := tc.entry.Inverse()
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTool, tool)
+			assert.Equal(t, tc.wantArgs, args)
+		})
+	}
+}
+
+func TestEntry_CheckDrift(t *testing.T) {
+	ctx := context.Background()
+	after, err := toMap(&unifi.WLAN{ID: "w1", Name: "Home 2"})
+	require.NoError(t, err)
+	update := Entry{ID: 1, Category: "update", Resource: "WLAN", Site: "default", ResourceID: "w1", After: after}
+	deleted := Entry{ID: 2, Category: "delete", Resource: "WLAN", Site: "default", ResourceID: "w1"}
+
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home 2"}, nil).Once()
+	assert.NoError(t, update.CheckDrift(ctx, client))
+
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home 3"}, nil).Once()
+	err = update.CheckDrift(ctx, client)
+	assert.ErrorIs(t, err, ErrDrift)
+	assert.Contains(t, err.Error(), "fields changed: name")
+
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1"}, nil).Once()
+	err = deleted.CheckDrift(ctx, client)
+	assert.ErrorIs(t, err, ErrDrift)
+	assert.Contains(t, err.Error(), "exists again")
+
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(nil, errors.New("not found")).Once()
+	assert.NoError(t, deleted.CheckDrift(ctx, client))
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/claytono/go-unifi-mcp/internal/gounifi"
@@ -39,6 +40,9 @@ type ToolInfo struct {
 	IsSetting  bool
 	IsV2       bool
 	Fields     []FieldSchema // Field schemas for create/update operations
+	// SecretFields are the JSON names of credential fields at any depth,
+	// see isSecretField.
+	SecretFields []string
 }
 
 // GeneratorConfig holds configuration for the generator.
@@ -92,6 +96,8 @@ func Generate(cfg GeneratorConfig) error {
 			IsV2:       r.IsV2(),
 			Operations: InferOperations(r),
 			Fields:     extractFieldSchemas(r),
+
+			SecretFields: extractSecretFields(r),
 		}
 		tools = append(tools, tool)
 	}
@@ -119,6 +125,10 @@ func Generate(cfg GeneratorConfig) error {
 		return fmt.Errorf("failed to render types template: %w", err)
 	}
 
+	if err := renderTemplate("templates/secrets.go.tmpl", filepath.Join(cfg.OutDir, "secrets.gen.go"), tools); err != nil {
+		return fmt.Errorf("failed to render secrets template: %w", err)
+	}
+
 	return nil
 }
 
@@ -217,6 +227,48 @@ func extractFieldSchemas(r *gounifi.Resource) []FieldSchema {
 	return schemas
 }
 
+// secretSuffixes mark credential fields that lack the x_ prefix.
+var secretSuffixes = []string{"password", "passphrase", "secret", "psk", "private_key", "token"}
+
+// isSecretField reports whether a field holds a credential: a string field
+// that UniFi hides with the x_ prefix (x_passphrase, x_secret, ...) or whose
+// name ends like a password, secret, key or token.
+func isSecretField(f *gounifi.FieldInfo) bool {
+	if strings.TrimPrefix(f.FieldType, "[]") != "string" {
+		return false
+	}
+	if strings.HasPrefix(f.JSONName, "x_") {
+		return true
+	}
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(f.JSONName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractSecretFields returns the sorted JSON names of the secret fields of
+// a Resource and of the types nested in it.
+func extractSecretFields(r *gounifi.Resource) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range r.Types {
+		if t == nil {
+			continue
+		}
+		for _, f := range t.Fields {
+			if f == nil || seen[f.JSONName] || !isSecretField(f) {
+				continue
+			}
+			seen[f.JSONName] = true
+			names = append(names, f.JSONName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // convertFieldToSchema converts a gounifi FieldInfo to a FieldSchema.
 func convertFieldToSchema(f *gounifi.FieldInfo) FieldSchema {
 	schema := FieldSchema{
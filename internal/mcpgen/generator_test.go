@@ -204,7 +204,8 @@ func TestGenerate_WithMockFields(t *testing.T) {
 	mockFieldJSON := `{
 		"name": ".{1,256}",
 		"enabled": "true|false",
-		"vlan_id": "^[0-9]{1,4}$"
+		"vlan_id": "^[0-9]{1,4}$",
+		"x_passphrase": "[\\s\\S]{8,63}"
 	}`
 	require.NoError(t, os.WriteFile(
 		filepath.Join(fieldsDir, "Network.json"),
@@ -246,6 +247,10 @@ func TestGenerate_WithMockFields(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, string(metadataContent), "Network")
 	assert.Contains(t, string(metadataContent), "AllToolMetadata")
+
+	secretsContent, err := os.ReadFile(filepath.Join(outDir, "secrets.gen.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(secretsContent), `"Network": {"x_passphrase"}`)
 }
 
 func TestGenerate_MissingV2Dir(t *testing.T) {
@@ -958,6 +963,37 @@ func TestExtractFieldSchemas(t *testing.T) {
 	})
 }
 
+func TestExtractSecretFields(t *testing.T) {
+	r := &gounifi.Resource{
+		StructName: "Test",
+		Types: map[string]*gounifi.FieldInfo{
+			"Test": {
+				FieldName: "Test",
+				Fields: map[string]*gounifi.FieldInfo{
+					"Name":         {JSONName: "name", FieldType: "string"},
+					"XPassphrase":  {JSONName: "x_passphrase", FieldType: "string"},
+					"XSSHEnabled":  {JSONName: "x_ssh_enabled", FieldType: "bool"},
+					"Psk":          {JSONName: "psk", FieldType: "string"},
+					"WPAPskRADIUS": {JSONName: "wpa_psk_radius", FieldType: "string"},
+					"Spacer":       nil,
+				},
+			},
+			"TestAuthServers": {
+				FieldName: "TestAuthServers",
+				Fields: map[string]*gounifi.FieldInfo{
+					"XSecret":  {JSONName: "x_secret", FieldType: "string"},
+					"XKeys":    {JSONName: "x_keys", FieldType: "string", IsArray: true},
+					"Password": {JSONName: "password", FieldType: "string"},
+				},
+			},
+			"Unused": nil,
+		},
+	}
+	assert.Equal(t, []string{"password", "psk", "x_keys", "x_passphrase", "x_secret"}, extractSecretFields(r))
+
+	assert.Nil(t, extractSecretFields(&gounifi.Resource{StructName: "Empty", Types: map[string]*gounifi.FieldInfo{}}))
+}
+
 func TestRenderTemplate_TemplateExecutionError(t *testing.T) {
 	outDir := t.TempDir()
 	outPath := filepath.Join(outDir, "test.go")
@@ -3,110 +3,253 @@ package meta
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
+	"github.com/claytono/go-unifi-mcp/internal/journal"
+	"github.com/claytono/go-unifi-mcp/internal/ratelimit"
+	"github.com/claytono/go-unifi-mcp/internal/render"
 	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
 	"github.com/filipowm/go-unifi/unifi"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// BatchHandler returns a handler that executes multiple tools in parallel.
+// DefaultBatchWorkers is how many calls of a batch run at once by default.
+const DefaultBatchWorkers = 8
+
+// BatchHandler returns a handler that executes multiple tools in parallel,
+// DefaultBatchWorkers at a time.
 func BatchHandler(client unifi.Client, registry map[string]generated.HandlerFunc) server.ToolHandlerFunc {
+	return LimitedBatchHandler(client, registry, DefaultBatchWorkers)
+}
+
+// Batch modes accepted by the mode argument.
+const (
+	BatchParallel   = "parallel"
+	BatchSequential = "sequential"
+)
+
+// LimitedBatchHandler returns a handler that executes multiple tools in
+// parallel, at most workers at a time; the others wait in order. Each result
+// reports how long its call waited for a worker and for the rate limit.
+// Each call may name a controller; calls that do not use the batch's.
+//
+// In sequential mode the calls run one after another, and their arguments
+// may refer to the results of earlier calls, see resolveRefs. With
+// stop_on_error, the calls after the first failure are skipped. A
+// transactional batch runs sequentially, stops at the first failure and then
+// reverses the changes already made, see runTransaction.
+func LimitedBatchHandler(client unifi.Client, registry map[string]generated.HandlerFunc, workers int) server.ToolHandlerFunc {
+	return batchHandler(client, registry, Options{BatchWorkers: workers})
+}
+
+// batchHandler returns the batch handler, see LimitedBatchHandler. The calls
+// return JSON so their results can be embedded and referred to; the batch
+// result is rendered in the format argument, or in opts.Format.
+func batchHandler(client unifi.Client, registry map[string]generated.HandlerFunc, opts Options) server.ToolHandlerFunc {
+	workers := max(opts.BatchWorkers, 1)
+	defaultFormat := opts.Format
+	if defaultFormat == "" {
+		defaultFormat = render.Default
+	}
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 		calls, ok := args["calls"].([]any)
 		if !ok || len(calls) == 0 {
 			return mcp.NewToolResultError("calls array is required and must not be empty"), nil
 		}
+		format, err := render.FromArgs(args, defaultFormat)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		ctx = render.WithDefault(ctx, render.JSON)
+		mode, _ := args["mode"].(string)
+		stopOnError, _ := args["stop_on_error"].(bool)
+		transactional, _ := args["transactional"].(bool)
+		if transactional && mode == "" {
+			mode = BatchSequential
+		}
+		switch mode {
+		case "", BatchParallel:
+			if stopOnError || transactional {
+				return mcp.NewToolResultError("stop_on_error and transactional require mode sequential"), nil
+			}
+		case BatchSequential:
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unknown mode %q: must be %s or %s", mode, BatchParallel, BatchSequential)), nil
+		}
 
-		results := make([]map[string]any, len(calls))
-		var wg sync.WaitGroup
-		var mu sync.Mutex
+		var results []map[string]any
+		switch {
+		case transactional:
+			results = runTransaction(ctx, client, registry, calls, args)
+		case mode == BatchSequential:
+			results, _ = runSequential(ctx, client, registry, calls, args, stopOnError, nil)
+		default:
+			results = runParallel(ctx, client, registry, calls, args, workers)
+		}
 
-		for i, call := range calls {
-			wg.Add(1)
-			go func(idx int, c any) {
-				defer wg.Done()
+		return render.Result(results, format, nil)
+	}
+}
 
-				result := map[string]any{
-					"index": idx,
+// runParallel runs the calls of a batch on up to workers goroutines.
+// References to other calls' results are refused, since those calls may not
+// have run yet.
+func runParallel(ctx context.Context, client unifi.Client, registry map[string]generated.HandlerFunc,
+	calls []any, batchArgs map[string]any, workers int,
+) []map[string]any {
+	start := time.Now()
+	results := make([]map[string]any, len(calls))
+	queue := make(chan int, len(calls))
+	for i := range calls {
+		queue <- i
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for range min(workers, len(calls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range queue {
+				if callMap, ok := calls[idx].(map[string]any); ok && hasRefs(callMap["arguments"]) {
+					results[idx] = callError(idx, callMap, "arguments refer to other calls' results, which requires mode sequential")
+					continue
 				}
+				results[idx] = runBatchCall(ctx, client, registry, idx, calls[idx], batchArgs, time.Since(start))
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
 
-				callMap, ok := c.(map[string]any)
-				if !ok {
-					result["error"] = "invalid call format: expected object with 'tool' and 'arguments'"
-					mu.Lock()
-					results[idx] = result
-					mu.Unlock()
-					return
-				}
+// runSequential runs the calls of a batch in order, resolving references to
+// the results of earlier calls in each call's arguments. With a transaction,
+// it also returns the changes each call made.
+func runSequential(ctx context.Context, client unifi.Client, registry map[string]generated.HandlerFunc,
+	calls []any, batchArgs map[string]any, stopOnError bool, tx *journal.Transaction,
+) ([]map[string]any, [][]journal.Entry) {
+	results := make([]map[string]any, len(calls))
+	changes := make([][]journal.Entry, len(calls))
+	if tx != nil {
+		ctx = journal.WithTransaction(ctx, tx)
+	}
+	stoppedAt := -1
+	for idx, c := range calls {
+		callMap, _ := c.(map[string]any)
+		if stoppedAt >= 0 {
+			results[idx] = callError(idx, callMap, fmt.Sprintf("skipped: call %d failed", stoppedAt))
+			results[idx]["skipped"] = true
+			continue
+		}
 
-				toolName, ok := callMap["tool"].(string)
-				if !ok || toolName == "" {
-					result["error"] = "tool name is required"
-					mu.Lock()
-					results[idx] = result
-					mu.Unlock()
-					return
-				}
-				result["tool"] = toolName
+		if callMap != nil && hasRefs(callMap["arguments"]) {
+			resolved, err := resolveRefs(callMap["arguments"], results, idx)
+			if err != nil {
+				results[idx] = callError(idx, callMap, err.Error())
+			} else {
+				c = withArguments(callMap, resolved)
+			}
+		}
+		if results[idx] == nil {
+			var applied int
+			if tx != nil {
+				applied = len(tx.Changes())
+			}
+			// Calls run as soon as the one before is done, so they only
+			// wait for the rate limit.
+			results[idx] = runBatchCall(ctx, client, registry, idx, c, batchArgs, 0)
+			if tx != nil {
+				changes[idx] = tx.Changes()[applied:]
+			}
+		}
+		if stopOnError && failed(results[idx]) {
+			stoppedAt = idx
+		}
+	}
+	return results, changes
+}
 
-				toolArgs, _ := callMap["arguments"].(map[string]any)
-				if toolArgs == nil {
-					toolArgs = make(map[string]any)
-				}
+// callError returns the result entry of a call that was not run.
+func callError(idx int, callMap map[string]any, msg string) map[string]any {
+	result := map[string]any{"index": idx, "error": msg}
+	if toolName, ok := callMap["tool"].(string); ok && toolName != "" {
+		result["tool"] = toolName
+	}
+	return result
+}
 
-				handlerFactory, ok := registry[toolName]
-				if !ok {
-					result["error"] = "unknown tool: " + toolName
-					mu.Lock()
-					results[idx] = result
-					mu.Unlock()
-					return
-				}
+// withArguments returns a copy of a call with its arguments replaced.
+func withArguments(callMap map[string]any, arguments any) map[string]any {
+	out := make(map[string]any, len(callMap))
+	for k, v := range callMap {
+		out[k] = v
+	}
+	out["arguments"] = arguments
+	return out
+}
 
-				// Build inner request
-				innerReq := mcp.CallToolRequest{}
-				innerReq.Params.Name = toolName
-				innerReq.Params.Arguments = toolArgs
-
-				handler := handlerFactory(client)
-				toolResult, err := handler(ctx, innerReq)
-				if err != nil {
-					result["error"] = err.Error()
-					mu.Lock()
-					results[idx] = result
-					mu.Unlock()
-					return
-				}
+// runBatchCall runs one call of a batch and returns its result entry. queued
+// is how long the call waited for a worker.
+func runBatchCall(ctx context.Context, client unifi.Client, registry map[string]generated.HandlerFunc,
+	idx int, c any, batchArgs map[string]any, queued time.Duration,
+) map[string]any {
+	result := map[string]any{
+		"index": idx,
+	}
 
-				// Extract the result content
-				if toolResult != nil && len(toolResult.Content) > 0 {
-					if textContent, ok := toolResult.Content[0].(mcp.TextContent); ok {
-						// Try to parse as JSON for cleaner output
-						var parsed any
-						if err := json.Unmarshal([]byte(textContent.Text), &parsed); err == nil {
-							result["result"] = parsed
-						} else {
-							result["result"] = textContent.Text
-						}
-					}
-					result["isError"] = toolResult.IsError
-				}
+	callMap, ok := c.(map[string]any)
+	if !ok {
+		result["error"] = "invalid call format: expected object with 'tool' and 'arguments'"
+		return result
+	}
 
-				mu.Lock()
-				results[idx] = result
-				mu.Unlock()
-			}(i, call)
-		}
+	toolName, ok := callMap["tool"].(string)
+	if !ok || toolName == "" {
+		result["error"] = "tool name is required"
+		return result
+	}
+	result["tool"] = toolName
 
-		wg.Wait()
+	toolArgs, _ := callMap["arguments"].(map[string]any)
+	toolArgs = withController(withController(toolArgs, callMap), batchArgs)
 
-		data, err := json.MarshalIndent(results, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError("failed to marshal results: " + err.Error()), nil
+	handlerFactory, ok := registry[toolName]
+	if !ok {
+		result["error"] = "unknown tool: " + toolName
+		return result
+	}
+
+	// Build inner request
+	innerReq := mcp.CallToolRequest{}
+	innerReq.Params.Name = toolName
+	innerReq.Params.Arguments = toolArgs
+
+	callCtx, rateLimited := ratelimit.TrackWait(ctx)
+	handler := handlerFactory(client)
+	toolResult, err := handler(callCtx, innerReq)
+	result["queueWaitMs"] = (queued + rateLimited()).Milliseconds()
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	// Extract the result content
+	if toolResult != nil && len(toolResult.Content) > 0 {
+		if textContent, ok := toolResult.Content[0].(mcp.TextContent); ok {
+			// Try to parse as JSON for cleaner output
+			var parsed any
+			if err := json.Unmarshal([]byte(textContent.Text), &parsed); err == nil {
+				result["result"] = parsed
+			} else {
+				result["result"] = textContent.Text
+			}
 		}
-		return mcp.NewToolResultText(string(data)), nil
+		result["isError"] = toolResult.IsError
 	}
+	return result
 }
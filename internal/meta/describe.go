@@ -0,0 +1,168 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// exampleEnumFields is the number of enum arguments, besides the required
+// ones, that the example of a create or update tool sets.
+const exampleEnumFields = 3
+
+// exampleLimit is the limit the example of a list tool sets.
+const exampleLimit = 20
+
+// toolDescription is the result of describe_tool.
+type toolDescription struct {
+	Name        string           `json:"name"`
+	Category    string           `json:"category"`
+	Resource    string           `json:"resource"`
+	Description string           `json:"description"`
+	InputSchema map[string]any   `json:"input_schema"`
+	Enums       map[string][]any `json:"enums,omitempty"` // allowed values by argument
+	Example     exampleCall      `json:"example"`
+}
+
+// exampleCall is an execute call of the described tool.
+type exampleCall struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// DescribeToolHandler returns a handler that describes one of the given
+// tools: its full input schema, the allowed values of its enum arguments and
+// an example call for execute. Only the given tools can be described.
+func DescribeToolHandler(tools []generated.ToolMetadata) server.ToolHandlerFunc {
+	byName := make(map[string]generated.ToolMetadata, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, _ := req.GetArguments()["tool"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("tool name is required"), nil
+		}
+		tool, ok := byName[name]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown tool: %s; use tool_index to find available tools", name)), nil
+		}
+
+		data, err := json.MarshalIndent(toolDescription{
+			Name:        tool.Name,
+			Category:    tool.Category,
+			Resource:    tool.Resource,
+			Description: tool.Description,
+			InputSchema: tool.InputSchema,
+			Enums:       schemaEnums(tool.InputSchema),
+			Example:     exampleCall{Tool: tool.Name, Arguments: exampleArguments(tool)},
+		}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError("failed to marshal description: " + err.Error()), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// schemaEnums returns the allowed values of the enum arguments of an input
+// schema, and of array arguments whose items are enums.
+func schemaEnums(schema map[string]any) map[string][]any {
+	props, _ := schema["properties"].(map[string]any)
+	var enums map[string][]any
+	for name, prop := range props {
+		values := enumValues(prop)
+		if values == nil {
+			continue
+		}
+		if enums == nil {
+			enums = make(map[string][]any)
+		}
+		enums[name] = values
+	}
+	return enums
+}
+
+// enumValues returns the enum of a property, or of its items.
+func enumValues(prop any) []any {
+	p, _ := prop.(map[string]any)
+	if values, ok := p["enum"].([]any); ok {
+		return values
+	}
+	if items, ok := p["items"].(map[string]any); ok {
+		values, _ := items["enum"].([]any)
+		return values
+	}
+	return nil
+}
+
+// exampleArguments returns example arguments for a tool: placeholders for its
+// required arguments, plus a name and a few enum arguments for create and
+// update tools, and a limit for list tools.
+func exampleArguments(tool generated.ToolMetadata) map[string]any {
+	props, _ := tool.InputSchema["properties"].(map[string]any)
+	args := make(map[string]any)
+	required, _ := tool.InputSchema["required"].([]any)
+	for _, r := range required {
+		if name, ok := r.(string); ok {
+			if prop, ok := props[name]; ok {
+				args[name] = exampleValue(name, prop)
+			}
+		}
+	}
+
+	switch tool.Category {
+	case "create", "update":
+		if prop, ok := props["name"]; ok {
+			args["name"] = exampleValue("name", prop)
+		}
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		added := 0
+		for _, name := range names {
+			if added == exampleEnumFields {
+				break
+			}
+			if _, ok := args[name]; ok || controlArgs[name] || enumValues(props[name]) == nil {
+				continue
+			}
+			args[name] = exampleValue(name, props[name])
+			added++
+		}
+	case "list":
+		if _, ok := props[generated.LimitArg]; ok {
+			args[generated.LimitArg] = exampleLimit
+		}
+	}
+	return args
+}
+
+// exampleValue returns a placeholder value for a property of the given
+// schema: its first enum value, or a value of its type.
+func exampleValue(name string, prop any) any {
+	p, _ := prop.(map[string]any)
+	if values, ok := p["enum"].([]any); ok && len(values) > 0 {
+		return values[0]
+	}
+	switch p["type"] {
+	case "integer", "number":
+		if minimum, ok := p["minimum"]; ok {
+			return minimum
+		}
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []any{exampleValue(name, p["items"])}
+	case "object":
+		return map[string]any{}
+	}
+	return "<" + name + ">"
+}
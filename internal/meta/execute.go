@@ -3,6 +3,7 @@ package meta
 import (
 	"context"
 
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
 	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
 	"github.com/filipowm/go-unifi/unifi"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -10,6 +11,8 @@ import (
 )
 
 // ExecuteHandler returns a handler that dispatches to any tool by name.
+// A controller argument next to the tool name is passed on to the tool
+// unless its arguments name a controller themselves.
 func ExecuteHandler(client unifi.Client, registry map[string]generated.HandlerFunc) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
@@ -19,9 +22,7 @@ func ExecuteHandler(client unifi.Client, registry map[string]generated.HandlerFu
 		}
 
 		toolArgs, _ := args["arguments"].(map[string]any)
-		if toolArgs == nil {
-			toolArgs = make(map[string]any)
-		}
+		toolArgs = withController(toolArgs, args)
 
 		handlerFactory, ok := registry[toolName]
 		if !ok {
@@ -37,3 +38,25 @@ func ExecuteHandler(client unifi.Client, registry map[string]generated.HandlerFu
 		return handler(ctx, innerReq)
 	}
 }
+
+// withController returns the tool arguments with the controller argument of
+// the enclosing call added if they do not name one. The arguments are copied
+// rather than modified.
+func withController(toolArgs, outer map[string]any) map[string]any {
+	controller, _ := outer[controllers.Arg].(string)
+	if controller == "" {
+		if toolArgs == nil {
+			return make(map[string]any)
+		}
+		return toolArgs
+	}
+	if _, ok := toolArgs[controllers.Arg]; ok {
+		return toolArgs
+	}
+	out := make(map[string]any, len(toolArgs)+1)
+	for k, v := range toolArgs {
+		out[k] = v
+	}
+	out[controllers.Arg] = controller
+	return out
+}
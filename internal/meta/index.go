@@ -10,14 +10,60 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// catalogEntry is the compact form of a tool in the catalog.
+type catalogEntry struct {
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	Resource    string  `json:"resource"`
+	Description string  `json:"description"`
+	Score       float64 `json:"score,omitempty"`
+}
+
 // ToolIndexHandler returns a handler that returns the filtered tool catalog.
-func ToolIndexHandler() server.ToolHandlerFunc {
+// Only the given tools are listed. With a query, the matching tools are
+// ranked and returned with their scores, best first. Tools are listed
+// compactly, by name, category, resource and the first line of their
+// description, unless schemas asks for their input schemas as well.
+func ToolIndexHandler(tools []generated.ToolMetadata) server.ToolHandlerFunc {
+	index := newSearchIndex(tools)
 	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 		category, _ := args["category"].(string)
 		resource, _ := args["resource"].(string)
+		query, _ := args["query"].(string)
+		schemas, _ := args["schemas"].(bool)
 
-		results := filterTools(generated.AllToolMetadata, category, resource)
+		filtered := filterTools(tools, category, resource)
+		var results any
+		switch {
+		case strings.TrimSpace(query) != "":
+			limit := DefaultSearchLimit
+			if l, ok := args["limit"].(float64); ok && l >= 1 {
+				limit = int(l)
+			}
+			matches := index.search(query, filtered, limit)
+			if schemas {
+				if matches == nil {
+					matches = []searchResult{}
+				}
+				results = matches
+				break
+			}
+			entries := make([]catalogEntry, len(matches))
+			for i, match := range matches {
+				entries[i] = compactEntry(match.ToolMetadata)
+				entries[i].Score = match.Score
+			}
+			results = entries
+		case schemas:
+			results = filtered
+		default:
+			entries := make([]catalogEntry, len(filtered))
+			for i, tool := range filtered {
+				entries[i] = compactEntry(tool)
+			}
+			results = entries
+		}
 		data, err := json.MarshalIndent(results, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError("failed to marshal results: " + err.Error()), nil
@@ -26,6 +72,17 @@ func ToolIndexHandler() server.ToolHandlerFunc {
 	}
 }
 
+// compactEntry returns the catalog entry of a tool.
+func compactEntry(tool generated.ToolMetadata) catalogEntry {
+	description, _, _ := strings.Cut(tool.Description, "\n")
+	return catalogEntry{
+		Name:        tool.Name,
+		Category:    tool.Category,
+		Resource:    tool.Resource,
+		Description: strings.TrimSpace(description),
+	}
+}
+
 // filterTools filters the tool metadata based on category and resource.
 func filterTools(tools []generated.ToolMetadata, category, resource string) []generated.ToolMetadata {
 	if category == "" && resource == "" {
@@ -1,36 +1,94 @@
 // Package meta provides meta-tools for lazy mode operation.
-// In lazy mode, only 3 meta-tools are registered instead of 242 direct tools,
+// In lazy mode, only 4 meta-tools are registered instead of 242 direct tools,
 // reducing context size from ~5000 tokens to ~200 tokens.
 package meta
 
 import (
-	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"fmt"
+
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	"github.com/claytono/go-unifi-mcp/internal/render"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
 	"github.com/filipowm/go-unifi/unifi"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// RegisterMetaTools registers the 3 meta-tools for lazy mode operation.
+// Options configures the meta-tools.
+type Options struct {
+	BatchWorkers int           // calls of a batch run at once (defaults to DefaultBatchWorkers)
+	Format       render.Format // default format of batch results (defaults to render.Default)
+}
+
+// RegisterMetaTools registers the 4 meta-tools for lazy mode operation.
 func RegisterMetaTools(s *server.MCPServer, client unifi.Client) {
-	registry := generated.GetHandlerRegistry()
+	RegisterMetaToolset(s, client, registry.DefaultToolset(), Options{})
+}
+
+// RegisterMetaToolset registers the 4 meta-tools, dispatching execute and
+// batch calls through the given toolset. If the toolset serves several
+// controllers, execute and batch take a controller argument.
+func RegisterMetaToolset(s *server.MCPServer, client unifi.Client, tools registry.Toolset, opts Options) {
+	if opts.BatchWorkers <= 0 {
+		opts.BatchWorkers = DefaultBatchWorkers
+	}
+	var executeController, batchController []mcp.ToolOption
+	if len(tools.Controllers) > 1 {
+		executeController = controllerOption("UniFi controller to run the tool on", tools.Controllers)
+		batchController = controllerOption("UniFi controller for calls that do not set their own 'controller'", tools.Controllers)
+	}
 
 	// tool_index - Returns filtered tool catalog
 	s.AddTool(mcp.NewTool("tool_index",
-		mcp.WithDescription("Returns the catalog of all available UniFi tools. Use this to discover tools before calling execute."),
+		mcp.WithDescription("Returns the catalog of all available UniFi tools: name, category, resource and a one-line description of each. Use this to discover tools, then describe_tool for their arguments, before calling execute."),
 		mcp.WithString("category", mcp.Description("Filter by operation type: list, get, create, update, delete")),
 		mcp.WithString("resource", mcp.Description("Filter by resource name (case-insensitive partial match)")),
-	), ToolIndexHandler())
+		mcp.WithString("query", mcp.Description("Search words, e.g. 'block a client' or 'port forwarding'; returns the best matches with scores")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of matches for a query (default: %d)", DefaultSearchLimit))),
+		mcp.WithBoolean("schemas", mcp.Description("Include the full description and input schema of each tool")),
+	), ToolIndexHandler(tools.Tools))
+
+	// describe_tool - Returns one tool's schema and an example call. Tools
+	// are the same on every controller, so it takes no controller argument.
+	s.AddTool(mcp.NewTool("describe_tool",
+		mcp.WithDescription("Describes a UniFi tool: its full input schema, the allowed values of its enum arguments and an example call for execute."),
+		mcp.WithString("tool", mcp.Required(), mcp.Description("Name of the tool to describe (e.g., 'create_network')")),
+	), DescribeToolHandler(tools.Tools))
 
 	// execute - Dispatches to any tool by name
-	s.AddTool(mcp.NewTool("execute",
+	s.AddTool(mcp.NewTool("execute", append([]mcp.ToolOption{
 		mcp.WithDescription("Executes any UniFi tool by name. Use tool_index first to discover available tools."),
 		mcp.WithString("tool", mcp.Required(), mcp.Description("Name of the tool to execute (e.g., 'list_network')")),
 		mcp.WithObject("arguments", mcp.Description("Arguments to pass to the tool")),
-	), ExecuteHandler(client, registry))
+	}, executeController...)...), ExecuteHandler(client, tools.Handlers))
 
 	// batch - Executes multiple tools in parallel
-	s.AddTool(mcp.NewTool("batch",
-		mcp.WithDescription("Executes multiple UniFi tools in parallel. Each call specifies a tool name and its arguments."),
-		mcp.WithArray("calls", mcp.Required(), mcp.Description("Array of tool calls, each with 'tool' (string) and 'arguments' (object)")),
-	), BatchHandler(client, registry))
+	s.AddTool(mcp.NewTool("batch", append([]mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("Executes multiple UniFi tools in parallel, up to %d at a time. Each call specifies a tool name and its arguments. Each result reports queueWaitMs, the time the call waited to start.", opts.BatchWorkers)),
+		mcp.WithArray("calls", mcp.Required(), mcp.Description("Array of tool calls, each with 'tool' (string) and 'arguments' (object). "+
+			"In sequential mode, argument strings may refer to earlier results, e.g. \"{{calls[0].result._id}}\"")),
+		mcp.WithString("mode", mcp.Enum(BatchParallel, BatchSequential),
+			mcp.Description("parallel (default) runs the calls at once; sequential runs them in order so later calls can use earlier results")),
+		mcp.WithBoolean("stop_on_error", mcp.Description("In sequential mode, skip the calls after the first one that fails")),
+		mcp.WithBoolean("transactional", mcp.Description("Run the calls in order and, if one fails, skip the rest and reverse the changes the earlier calls made. "+
+			"Results mark reversed calls with rolledBack, or rollbackError if reversing failed")),
+		mcp.WithString(render.Arg, mcp.Enum(formatNames()...), mcp.Description("Output format of the batch result (default: the server's format)")),
+	}, batchController...)...), batchHandler(client, tools.Handlers, opts))
+}
+
+// controllerOption declares the controller argument of a meta-tool.
+func controllerOption(description string, names []string) []mcp.ToolOption {
+	return []mcp.ToolOption{mcp.WithString(controllers.Arg,
+		mcp.Description(fmt.Sprintf("%s (default: %s)", description, names[0])),
+		mcp.Enum(names...),
+	)}
+}
+
+// formatNames returns the names of the output formats.
+func formatNames() []string {
+	names := make([]string, len(render.Formats))
+	for i, f := range render.Formats {
+		names[i] = string(f)
+	}
+	return names
 }
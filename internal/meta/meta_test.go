@@ -3,19 +3,31 @@ package meta
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
-
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/cache"
+	"github.com/claytono/go-unifi-mcp/internal/confirm"
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	"github.com/claytono/go-unifi-mcp/internal/journal"
+	"github.com/claytono/go-unifi-mcp/internal/render"
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
 	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
 	"github.com/filipowm/go-unifi/unifi"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestToolIndex_ReturnsAllTools(t *testing.T) {
-	handler := ToolIndexHandler()
+	handler := ToolIndexHandler(generated.AllToolMetadata)
 
 	req := mcp.CallToolRequest{}
 	req.Params.Arguments = map[string]any{}
@@ -36,7 +48,7 @@ func TestToolIndex_ReturnsAllTools(t *testing.T) {
 }
 
 func TestToolIndex_FilterByCategory(t *testing.T) {
-	handler := ToolIndexHandler()
+	handler := ToolIndexHandler(generated.AllToolMetadata)
 
 	tests := []struct {
 		category string
@@ -77,7 +89,7 @@ func TestToolIndex_FilterByCategory(t *testing.T) {
 }
 
 func TestToolIndex_FilterByResource(t *testing.T) {
-	handler := ToolIndexHandler()
+	handler := ToolIndexHandler(generated.AllToolMetadata)
 
 	req := mcp.CallToolRequest{}
 	req.Params.Arguments = map[string]any{
@@ -104,7 +116,7 @@ func TestToolIndex_FilterByResource(t *testing.T) {
 }
 
 func TestToolIndex_FilterByCategoryAndResource(t *testing.T) {
-	handler := ToolIndexHandler()
+	handler := ToolIndexHandler(generated.AllToolMetadata)
 
 	req := mcp.CallToolRequest{}
 	req.Params.Arguments = map[string]any{
@@ -129,7 +141,7 @@ func TestToolIndex_FilterByCategoryAndResource(t *testing.T) {
 }
 
 func TestToolIndex_CaseInsensitiveFilters(t *testing.T) {
-	handler := ToolIndexHandler()
+	handler := ToolIndexHandler(generated.AllToolMetadata)
 
 	req := mcp.CallToolRequest{}
 	req.Params.Arguments = map[string]any{
@@ -151,6 +163,220 @@ func TestToolIndex_CaseInsensitiveFilters(t *testing.T) {
 	assert.Greater(t, len(tools), 0)
 }
 
+func TestToolIndex_OnlyListsGivenTools(t *testing.T) {
+	handler := ToolIndexHandler([]generated.ToolMetadata{
+		{Name: "list_network", Category: "list", Resource: "Network"},
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"category": "delete"}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	var tools []generated.ToolMetadata
+	err = json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &tools)
+	require.NoError(t, err)
+	assert.Empty(t, tools)
+}
+
+// searchTools calls tool_index over all tools with the given arguments.
+func searchTools(t *testing.T, args map[string]any) []searchResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := ToolIndexHandler(generated.AllToolMetadata)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var results []searchResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &results))
+	return results
+}
+
+func TestToolIndex_Query(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"block a client", "update_user"},          // synonyms client→User, block→update
+		{"port forwarding", "create_port_forward"}, // stemmed and split resource name
+		{"change wifi password", "update_wlan"},    // wifi→WLAN, password→x_passphrase
+		{"ssid", "create_wlan"},                    // synonym only
+		{"delete firewall rules", "delete_firewall_rule"},
+		{"radius secret", "update_setting_radius"}, // field names
+	}
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			results := searchTools(t, map[string]any{"query": tc.query})
+			require.NotEmpty(t, results)
+			assert.Equal(t, tc.want, results[0].Name)
+			assert.LessOrEqual(t, len(results), DefaultSearchLimit)
+			for i := 1; i < len(results); i++ {
+				assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+			}
+		})
+	}
+}
+
+func TestToolIndex_QueryWithFilters(t *testing.T) {
+	results := searchTools(t, map[string]any{"query": "wifi", "category": "list", "limit": float64(2)})
+	require.Len(t, results, 2)
+	assert.Equal(t, "list_wlan", results[0].Name)
+	assert.Positive(t, results[0].Score)
+	for _, r := range results {
+		assert.Equal(t, "list", r.Category)
+	}
+
+	assert.Empty(t, searchTools(t, map[string]any{"query": "kubernetes"}))
+	assert.Empty(t, searchTools(t, map[string]any{"query": "the of a"}), "stop words match nothing")
+}
+
+func TestToolIndex_QueryIgnoresControlArgs(t *testing.T) {
+	all := func(generated.ToolMetadata) bool { return true }
+	tools := registry.DefaultToolset().
+		WithArg(cache.Arg, cache.ArgSchema, all).
+		WithArg(render.Arg, render.ArgSchema, all).Tools
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"query": "cache format", "limit": float64(len(tools))}
+	result, err := ToolIndexHandler(tools)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	// Arguments every tool has say nothing about what a tool is for.
+	var results []searchResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &results))
+	assert.Less(t, len(results), len(tools)/10)
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"port", "forward"}, tokenize("PortForward"))
+	assert.Equal(t, []string{"list", "radius", "profile", "alias"}, tokenize("list_radius_profiles alias"))
+	assert.Equal(t, []string{"policy", "address", "switch", "forward", "wlan"}, tokenize("policies addresses switches forwarding wlans"))
+}
+
+func TestToolIndex_Compact(t *testing.T) {
+	handler := ToolIndexHandler([]generated.ToolMetadata{{
+		Name: "create_network", Category: "create", Resource: "Network",
+		Description: "Create new Network\nSee describe_tool for the fields.",
+		InputSchema: map[string]any{"type": "object"},
+	}})
+	call := func(args map[string]any) []map[string]any {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &entries))
+		return entries
+	}
+
+	assert.Equal(t, []map[string]any{{
+		"name": "create_network", "category": "create", "resource": "Network", "description": "Create new Network",
+	}}, call(nil))
+
+	entries := call(map[string]any{"query": "network"})
+	require.Len(t, entries, 1)
+	assert.Positive(t, entries[0]["score"])
+	assert.NotContains(t, entries[0], "InputSchema")
+
+	// schemas returns the full metadata.
+	entries = call(map[string]any{"schemas": true})
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{"type": "object"}, entries[0]["InputSchema"])
+	assert.Equal(t, "Create new Network\nSee describe_tool for the fields.", entries[0]["Description"])
+	entries = call(map[string]any{"query": "network", "schemas": true})
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0], "InputSchema")
+}
+
+// describeTool calls describe_tool over all tools.
+func describeTool(t *testing.T, name string) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"tool": name}
+	result, err := DescribeToolHandler(generated.AllToolMetadata)(context.Background(), req)
+	require.NoError(t, err)
+	return result
+}
+
+// describe returns the description of a tool.
+func describe(t *testing.T, name string) toolDescription {
+	t.Helper()
+	result := describeTool(t, name)
+	require.False(t, result.IsError)
+	var desc toolDescription
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &desc))
+	return desc
+}
+
+func TestDescribeTool(t *testing.T) {
+	desc := describe(t, "create_network")
+	assert.Equal(t, "create_network", desc.Name)
+	assert.Equal(t, "Network", desc.Resource)
+	assert.Contains(t, desc.InputSchema, "properties")
+	assert.Equal(t, []any{"corporate", "guest", "remote-user-vpn", "site-vpn", "vlan-only", "vpn-client", "wan"}, desc.Enums["purpose"])
+	assert.Equal(t, "create_network", desc.Example.Tool)
+	assert.Equal(t, "<name>", desc.Example.Arguments["name"])
+	enums := 0
+	for name, value := range desc.Example.Arguments {
+		if values, ok := desc.Enums[name]; ok {
+			assert.Equal(t, values[0], value)
+			enums++
+		}
+	}
+	assert.Equal(t, exampleEnumFields, enums)
+
+	assert.Equal(t, map[string]any{"id": "<id>"}, describe(t, "get_network").Example.Arguments)
+	assert.Equal(t, map[string]any{"limit": float64(exampleLimit)}, describe(t, "list_network").Example.Arguments)
+}
+
+func TestDescribeTool_ExampleSkipsControlArgs(t *testing.T) {
+	client := servermocks.NewClient(t)
+	set, err := controllers.NewSet([]controllers.Controller{{Name: "home", Client: client}, {Name: "lab", Client: client}})
+	require.NoError(t, err)
+	all := func(generated.ToolMetadata) bool { return true }
+	tools := set.Toolset(registry.DefaultToolset().WithArg(render.Arg, render.ArgSchema, all)).Tools
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"tool": "create_network"}
+	result, err := DescribeToolHandler(tools)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var desc toolDescription
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &desc))
+
+	// The schema offers the control arguments, the example shows real fields.
+	assert.Contains(t, desc.Enums, controllers.Arg)
+	assert.Contains(t, desc.Enums, render.Arg)
+	assert.NotContains(t, desc.Example.Arguments, controllers.Arg)
+	assert.NotContains(t, desc.Example.Arguments, render.Arg)
+	assert.Equal(t, describe(t, "create_network").Example.Arguments, desc.Example.Arguments)
+}
+
+func TestDescribeTool_Errors(t *testing.T) {
+	result := describeTool(t, "launch_rocket")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "unknown tool: launch_rocket")
+
+	result = describeTool(t, "")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "tool name is required")
+}
+
+func TestExampleValue(t *testing.T) {
+	assert.Equal(t, "<mac>", exampleValue("mac", map[string]any{"type": "string"}))
+	assert.Equal(t, 1, exampleValue("limit", map[string]any{"type": "integer", "minimum": 1}))
+	assert.Equal(t, 1, exampleValue("vlan", map[string]any{"type": "integer"}))
+	assert.Equal(t, true, exampleValue("enabled", map[string]any{"type": "boolean"}))
+	assert.Equal(t, map[string]any{}, exampleValue("config", map[string]any{"type": "object"}))
+	assert.Equal(t, []any{"a"}, exampleValue("modes", map[string]any{
+		"type": "array", "items": map[string]any{"type": "string", "enum": []any{"a", "b"}},
+	}))
+}
+
 func TestExecute_UnknownToolReturnsError(t *testing.T) {
 	registry := make(map[string]generated.HandlerFunc)
 	handler := ExecuteHandler(nil, registry)
@@ -293,6 +519,48 @@ func TestBatch_ParallelExecution(t *testing.T) {
 	}
 }
 
+func TestBatch_BoundedConcurrency(t *testing.T) {
+	var running, peak int32
+	registry := map[string]generated.HandlerFunc{
+		"test_tool": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return mcp.NewToolResultText(`{"result": "ok"}`), nil
+			}
+		},
+	}
+
+	calls := make([]any, 6)
+	for i := range calls {
+		calls[i] = map[string]any{"tool": "test_tool", "arguments": map[string]any{}}
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"calls": calls}
+
+	result, err := LimitedBatchHandler(nil, registry, 2)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &results))
+	require.Len(t, results, 6)
+	for i, r := range results {
+		assert.Equal(t, float64(i), r["index"])
+		assert.Contains(t, r, "queueWaitMs")
+	}
+	// The last calls waited for two rounds of earlier calls.
+	assert.GreaterOrEqual(t, results[5]["queueWaitMs"], float64(30))
+}
+
 func TestBatch_PartialFailure(t *testing.T) {
 	mockHandler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		return mcp.NewToolResultText(`{"result": "ok"}`), nil
@@ -580,3 +848,542 @@ func TestBatch_NonJSONResult(t *testing.T) {
 	// Result should be stored as plain text string
 	assert.Equal(t, "plain text, not JSON", results[0]["result"])
 }
+
+func wlanMap(t *testing.T, w *unifi.WLAN) map[string]any {
+	t.Helper()
+	raw, err := json.Marshal(w)
+	require.NoError(t, err)
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(raw, &m))
+	return m
+}
+
+func TestUndo_NothingToUndo(t *testing.T) {
+	j := journal.New(10)
+	handler := UndoHandler(nil, nil, j)
+
+	req := mcp.CallToolRequest{}
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "no changes to undo")
+
+	req.Params.Arguments = map[string]any{"change_id": float64(9)}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "change 9 not found")
+}
+
+func TestUndo_RevertsUpdate(t *testing.T) {
+	j := journal.New(10)
+	entry := j.Record(journal.Entry{
+		Tool: "update_wlan", Category: "update", Resource: "WLAN", Site: "default", ResourceID: "w1",
+		Before: wlanMap(t, &unifi.WLAN{ID: "w1", Name: "Home"}),
+		After:  wlanMap(t, &unifi.WLAN{ID: "w1", Name: "Home 2"}),
+	})
+
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home 2"}, nil).Once()
+
+	var gotArgs map[string]any
+	var gotCtx context.Context
+	registry := map[string]generated.HandlerFunc{
+		"update_wlan": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				gotCtx, gotArgs = ctx, req.GetArguments()
+				return mcp.NewToolResultText(`{"_id": "w1", "name": "Home"}`), nil
+			}
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	result, err := UndoHandler(client, registry, j)(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Home", gotArgs["name"])
+	assert.Equal(t, "w1", gotArgs["id"])
+	assert.Equal(t, "default", gotArgs["site"])
+
+	// The inverse call is tagged so the journal links it to the change.
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home 2"}, nil).Once()
+	inverse := mcp.CallToolRequest{}
+	inverse.Params.Arguments = gotArgs
+	_, err = j.Middleware(client, generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"},
+		func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(`{}`), nil
+		})(gotCtx, inverse)
+	require.NoError(t, err)
+	undone, ok := j.Get(entry.ID)
+	require.True(t, ok)
+	assert.NotZero(t, undone.UndoneBy)
+
+	req.Params.Arguments = map[string]any{"change_id": float64(entry.ID)}
+	result, err = UndoHandler(client, registry, j)(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "already undone")
+}
+
+func TestUndo_RefusesOnDriftUnlessForced(t *testing.T) {
+	j := journal.New(10)
+	j.Record(journal.Entry{
+		Tool: "update_wlan", Category: "update", Resource: "WLAN", Site: "default", ResourceID: "w1",
+		Before: wlanMap(t, &unifi.WLAN{ID: "w1", Name: "Home"}),
+		After:  wlanMap(t, &unifi.WLAN{ID: "w1", Name: "Home 2"}),
+	})
+
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home 3"}, nil).Once()
+
+	var calls atomic.Int32
+	registry := map[string]generated.HandlerFunc{
+		"update_wlan": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls.Add(1)
+				return mcp.NewToolResultText(`{}`), nil
+			}
+		},
+	}
+	handler := UndoHandler(client, registry, j)
+
+	req := mcp.CallToolRequest{}
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "fields changed: name")
+	assert.Contains(t, text, "use force")
+	assert.Equal(t, int32(0), calls.Load())
+
+	req.Params.Arguments = map[string]any{"force": true}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestUndo_InverseToolUnavailable(t *testing.T) {
+	j := journal.New(10)
+	j.Record(journal.Entry{Tool: "create_wlan", Category: "create", Resource: "WLAN", Site: "default", ResourceID: "w2"})
+
+	result, err := UndoHandler(nil, map[string]generated.HandlerFunc{}, j)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "tool delete_wlan is not available")
+}
+
+func TestListChanges_RedactsAndLimits(t *testing.T) {
+	j := journal.New(10)
+	j.Record(journal.Entry{Tool: "create_wlan", Category: "create", Resource: "WLAN", After: map[string]any{"name": "Guest"}})
+	j.Record(journal.Entry{Tool: "update_wlan", Category: "update", Resource: "WLAN",
+		Before: map[string]any{"x_passphrase": "old", "x_mesh_essid": "mesh"}, After: map[string]any{"x_passphrase": "new"}})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"limit": float64(1)}
+	result, err := ListChangesHandler(j)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var entries []journal.Entry
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "update_wlan", entries[0].Tool)
+	assert.Equal(t, "[REDACTED]", entries[0].Before["x_passphrase"])
+	assert.Equal(t, "[REDACTED]", entries[0].After["x_passphrase"])
+	assert.Equal(t, "mesh", entries[0].Before["x_mesh_essid"], "not a secret field of WLAN")
+
+	// The journal keeps the secrets needed to undo the change.
+	kept, ok := j.Get(entries[0].ID)
+	require.True(t, ok)
+	assert.Equal(t, "old", kept.Before["x_passphrase"])
+}
+
+func TestBatch_PassesControllerToCalls(t *testing.T) {
+	var seen sync.Map
+	registry := map[string]generated.HandlerFunc{
+		"test_tool": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				args := req.GetArguments()
+				seen.Store(args["n"], args[controllers.Arg])
+				return mcp.NewToolResultText(`{}`), nil
+			}
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"controller": "lab",
+		"calls": []any{
+			map[string]any{"tool": "test_tool", "arguments": map[string]any{"n": "batch"}},
+			map[string]any{"tool": "test_tool", "controller": "home", "arguments": map[string]any{"n": "call"}},
+			map[string]any{"tool": "test_tool", "controller": "home", "arguments": map[string]any{"n": "args", "controller": "office"}},
+		},
+	}
+	result, err := BatchHandler(nil, registry)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	for n, want := range map[string]string{"batch": "lab", "call": "home", "args": "office"} {
+		got, _ := seen.Load(n)
+		assert.Equal(t, want, got, n)
+	}
+
+	// Execute passes its controller the same way.
+	req.Params.Arguments = map[string]any{"tool": "test_tool", "controller": "lab", "arguments": map[string]any{"n": "execute"}}
+	_, err = ExecuteHandler(nil, registry)(context.Background(), req)
+	require.NoError(t, err)
+	got, _ := seen.Load("execute")
+	assert.Equal(t, "lab", got)
+}
+
+// batchResults runs a batch and returns its decoded result entries.
+func batchResults(t *testing.T, registry map[string]generated.HandlerFunc, args map[string]any) []map[string]any {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := BatchHandler(nil, registry)(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content)
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &results))
+	return results
+}
+
+func TestBatch_SequentialReferences(t *testing.T) {
+	var order []string
+	var wlanArgs map[string]any
+	registry := map[string]generated.HandlerFunc{
+		"create_network": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				order = append(order, "create_network")
+				return mcp.NewToolResultText(`{"_id": "net1", "name": "iot", "vlan": 30, "tags": ["a", "b"]}`), nil
+			}
+		},
+		"create_wlan": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				order = append(order, "create_wlan")
+				wlanArgs = req.GetArguments()
+				return mcp.NewToolResultText(`{"_id": "wlan1"}`), nil
+			}
+		},
+	}
+
+	results := batchResults(t, registry, map[string]any{
+		"mode": "sequential",
+		"calls": []any{
+			map[string]any{"tool": "create_network", "arguments": map[string]any{"name": "iot"}},
+			map[string]any{"tool": "create_wlan", "arguments": map[string]any{
+				"networkconf_id": "{{calls[0].result._id}}",
+				"name":           "{{ calls[0].result.name }}-wifi on VLAN {{calls[0].result.vlan}}",
+				"vlan":           "{{calls[0].result.vlan}}",
+				"nested":         []any{map[string]any{"tag": "{{calls[0].result.tags[1]}}"}},
+			}},
+		},
+	})
+
+	assert.Equal(t, []string{"create_network", "create_wlan"}, order)
+	assert.Equal(t, "net1", wlanArgs["networkconf_id"])
+	assert.Equal(t, "iot-wifi on VLAN 30", wlanArgs["name"])
+	assert.Equal(t, float64(30), wlanArgs["vlan"], "a whole-string reference keeps the value's type")
+	assert.Equal(t, []any{map[string]any{"tag": "b"}}, wlanArgs["nested"])
+	assert.Equal(t, map[string]any{"_id": "wlan1"}, results[1]["result"])
+}
+
+func TestBatch_SequentialQueueWait(t *testing.T) {
+	registry := map[string]generated.HandlerFunc{
+		"slow_tool": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				time.Sleep(30 * time.Millisecond)
+				return mcp.NewToolResultText(`{}`), nil
+			}
+		},
+	}
+	call := map[string]any{"tool": "slow_tool"}
+	results := batchResults(t, registry, map[string]any{"mode": "sequential", "calls": []any{call, call, call}})
+
+	// The run time of earlier calls is not queue wait.
+	for _, r := range results {
+		assert.Equal(t, float64(0), r["queueWaitMs"])
+	}
+}
+
+func TestBatch_SequentialStopOnError(t *testing.T) {
+	var calls int32
+	registry := map[string]generated.HandlerFunc{
+		"fail": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError("boom"), nil
+			}
+		},
+		"ok": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				atomic.AddInt32(&calls, 1)
+				return mcp.NewToolResultText(`{}`), nil
+			}
+		},
+	}
+	batch := []any{
+		map[string]any{"tool": "ok"},
+		map[string]any{"tool": "fail"},
+		map[string]any{"tool": "ok"},
+		map[string]any{"tool": "ok", "arguments": map[string]any{"id": "{{calls[1].result._id}}"}},
+	}
+
+	results := batchResults(t, registry, map[string]any{"mode": "sequential", "stop_on_error": true, "calls": batch})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, true, results[1]["isError"])
+	for _, r := range results[2:] {
+		assert.Equal(t, true, r["skipped"])
+		assert.Equal(t, "skipped: call 1 failed", r["error"])
+		assert.Equal(t, "ok", r["tool"])
+	}
+
+	// Without stop_on_error the other calls run, but a reference to the
+	// failed call cannot be resolved.
+	results = batchResults(t, registry, map[string]any{"mode": "sequential", "calls": batch})
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.NotContains(t, results[2], "error")
+	assert.Equal(t, "{{calls[1].result._id}}: call 1 did not succeed", results[3]["error"])
+}
+
+func TestBatch_ReferenceErrors(t *testing.T) {
+	registry := map[string]generated.HandlerFunc{
+		"ok": func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(`{"_id": "x", "list": [1]}`), nil
+			}
+		},
+	}
+	for ref, want := range map[string]string{
+		"{{calls[1].result._id}}":      "can only refer to an earlier call",
+		"{{calls[0].result.name}}":     `no field "name"`,
+		"{{calls[0].result.list[3]}}":  "index 3 out of range (length 1)",
+		"{{calls[0].result[0]}}":       "cannot index an object",
+		"{{calls[0].result._id.more}}": `cannot look up "more" in string`,
+	} {
+		results := batchResults(t, registry, map[string]any{
+			"mode": "sequential",
+			"calls": []any{
+				map[string]any{"tool": "ok"},
+				map[string]any{"tool": "ok", "arguments": map[string]any{"id": "prefix-" + ref}},
+			},
+		})
+		assert.Contains(t, results[1]["error"], want, ref)
+	}
+}
+
+func TestBatch_ModeErrors(t *testing.T) {
+	registry := map[string]generated.HandlerFunc{}
+	calls := []any{map[string]any{"tool": "ok", "arguments": map[string]any{"id": "{{calls[0].result._id}}"}}}
+
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"calls": calls, "mode": "random"}, `unknown mode "random"`},
+		{map[string]any{"calls": calls, "stop_on_error": true}, "stop_on_error and transactional require mode sequential"},
+		{map[string]any{"calls": calls, "mode": "parallel", "transactional": true}, "stop_on_error and transactional require mode sequential"},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tc.args
+		result, err := BatchHandler(nil, registry)(context.Background(), req)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.want)
+	}
+
+	// References need sequential mode.
+	results := batchResults(t, registry, map[string]any{"calls": calls})
+	assert.Contains(t, results[0]["error"], "requires mode sequential")
+}
+
+// transactionToolset returns tools whose mutating calls are recorded for
+// transactions, and the calls made to them in order.
+func transactionToolset(t *testing.T, failing ...string) (map[string]generated.HandlerFunc, *[]string) {
+	t.Helper()
+	var order []string
+	handler := func(name, response string) generated.HandlerFunc {
+		return func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				args, _ := json.Marshal(req.GetArguments())
+				order = append(order, name+" "+string(args))
+				for _, f := range failing {
+					if f == name {
+						return mcp.NewToolResultError(name + " failed"), nil
+					}
+				}
+				return mcp.NewToolResultText(response), nil
+			}
+		}
+	}
+	tools := registry.Toolset{
+		Tools: []generated.ToolMetadata{
+			{Name: "create_network", Category: "create", Resource: "Network"},
+			{Name: "delete_network", Category: "delete", Resource: "Network"},
+			{Name: "update_wlan", Category: "update", Resource: "WLAN"},
+			{Name: "create_wlan", Category: "create", Resource: "WLAN"},
+		},
+		Handlers: map[string]generated.HandlerFunc{
+			"create_network": handler("create_network", `{"_id": "n1", "name": "IoT"}`),
+			"delete_network": handler("delete_network", `{}`),
+			"update_wlan":    handler("update_wlan", `{"_id": "w1", "name": "Home 2"}`),
+			"create_wlan":    handler("create_wlan", `{"_id": "w2"}`),
+		},
+	}
+	var changes *journal.Journal
+	return tools.Wrap(changes.Middleware).Handlers, &order
+}
+
+func transactionResults(t *testing.T, client unifi.Client, handlers map[string]generated.HandlerFunc) []map[string]any {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"transactional": true,
+		"calls": []any{
+			map[string]any{"tool": "create_network", "arguments": map[string]any{"site": "default", "name": "IoT"}},
+			map[string]any{"tool": "update_wlan", "arguments": map[string]any{"site": "default", "id": "w1", "name": "Home 2"}},
+			map[string]any{"tool": "create_wlan", "arguments": map[string]any{"site": "default", "networkconf_id": "{{calls[0].result._id}}"}},
+			map[string]any{"tool": "create_wlan", "arguments": map[string]any{"site": "default"}},
+		},
+	}
+	result, err := BatchHandler(client, handlers)(context.Background(), req)
+	require.NoError(t, err)
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &results))
+	return results
+}
+
+func TestBatch_TransactionRollsBack(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+	handlers, order := transactionToolset(t, "create_wlan")
+
+	results := transactionResults(t, client, handlers)
+	assert.Equal(t, true, results[0]["rolledBack"])
+	assert.Equal(t, true, results[1]["rolledBack"])
+	assert.Equal(t, true, results[2]["isError"])
+	assert.NotContains(t, results[2], "rolledBack")
+	assert.Equal(t, true, results[3]["skipped"])
+
+	// The changes are reversed newest first.
+	require.Len(t, *order, 5)
+	assert.Contains(t, (*order)[2], `"networkconf_id":"n1"`)
+	assert.True(t, strings.HasPrefix((*order)[3], "update_wlan "), (*order)[3])
+	assert.Contains(t, (*order)[3], `"name":"Home"`)
+	assert.Equal(t, `delete_network {"id":"n1","site":"default"}`, (*order)[4])
+}
+
+// elicitorFunc answers elicitation requests.
+type elicitorFunc func(context.Context, mcp.ElicitationRequest) (*mcp.ElicitationResult, error)
+
+func (f elicitorFunc) RequestElicitation(ctx context.Context, req mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return f(ctx, req)
+}
+
+func TestBatch_TransactionRollsBackWithoutConfirmation(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+	handlers, order := transactionToolset(t, "create_wlan")
+
+	// Deleting the created network needs confirmation, which this client
+	// cannot give; the rollback must not ask for it.
+	confirmer, err := confirm.New(elicitorFunc(func(context.Context, mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+		t.Error("a rollback asked for confirmation")
+		return nil, errors.New("unexpected elicitation")
+	}), []string{"category:delete"})
+	require.NoError(t, err)
+	tools := registry.Toolset{Tools: []generated.ToolMetadata{
+		{Name: "create_network", Category: "create", Resource: "Network"},
+		{Name: "delete_network", Category: "delete", Resource: "Network"},
+		{Name: "update_wlan", Category: "update", Resource: "WLAN"},
+		{Name: "create_wlan", Category: "create", Resource: "WLAN"},
+	}, Handlers: handlers}
+	handlers = tools.Wrap(confirmer.Middleware).Handlers
+
+	results := transactionResults(t, client, handlers)
+	assert.Equal(t, true, results[0]["rolledBack"], results[0]["rollbackError"])
+	assert.Equal(t, true, results[1]["rolledBack"])
+	assert.Equal(t, `delete_network {"id":"n1","site":"default"}`, (*order)[len(*order)-1])
+
+	// Outside a rollback the same delete still needs confirmation.
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"site": "default", "id": "n1"}
+	result, err := handlers["delete_network"](client)(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "confirmation required")
+}
+
+func TestBatch_TransactionReportsRollbackFailures(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+	handlers, _ := transactionToolset(t, "create_wlan", "delete_network")
+
+	results := transactionResults(t, client, handlers)
+	assert.Equal(t, "delete_network: delete_network failed", results[0]["rollbackError"])
+	assert.NotContains(t, results[0], "rolledBack")
+	assert.Equal(t, true, results[1]["rolledBack"])
+}
+
+func TestBatch_TransactionSucceeds(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home"}, nil).Once()
+	handlers, order := transactionToolset(t)
+
+	results := transactionResults(t, client, handlers)
+	assert.Len(t, *order, 4)
+	for _, r := range results {
+		assert.Equal(t, false, r["isError"])
+		assert.NotContains(t, r, "rolledBack")
+	}
+}
+
+func TestBatch_Fields(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{
+		{ID: "n1", Name: "LAN", Purpose: "corporate"},
+	}, nil).Once()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"calls": []any{
+		map[string]any{"tool": "list_network", "arguments": map[string]any{"fields": []any{"name", "purpose"}}},
+	}}
+	result, err := BatchHandler(client, generated.GetHandlerRegistry())(context.Background(), req)
+	require.NoError(t, err)
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &results))
+	assert.Equal(t, []any{map[string]any{"name": "LAN", "purpose": "corporate"}}, results[0]["result"])
+}
+
+func TestBatch_Format(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{{ID: "n1", Name: "LAN"}}, nil).Once()
+
+	// Each call returns JSON, so its result is embedded in the batch result
+	// rendered as YAML.
+	handler := render.Middleware(render.YAML)(client, generated.ToolMetadata{Name: "list_network"},
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return generated.GetHandlerRegistry()["list_network"](client)(ctx, req)
+		})
+	registry := map[string]generated.HandlerFunc{
+		"list_network": func(unifi.Client) server.ToolHandlerFunc { return handler },
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"format": "yaml",
+		"calls": []any{
+			map[string]any{"tool": "list_network", "arguments": map[string]any{"fields": []any{"name"}}},
+		},
+	}
+	result, err := batchHandler(client, registry, Options{BatchWorkers: 1, Format: render.JSON})(context.Background(), req)
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "  result:\n    - name: LAN\n")
+
+	req.Params.Arguments = map[string]any{"format": "xml", "calls": []any{map[string]any{"tool": "list_network"}}}
+	result, err = batchHandler(client, registry, Options{})(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
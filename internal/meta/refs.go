@@ -0,0 +1,149 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// refPattern matches a reference to the result of an earlier batch call,
+// such as {{calls[0].result._id}} or {{calls[1].result[0].name}}.
+var refPattern = regexp.MustCompile(`\{\{\s*calls\[(\d+)\]((?:\.[A-Za-z0-9_-]+|\[\d+\])*)\s*\}\}`)
+
+// pathSegment matches one step of a reference path: a field or an index.
+var pathSegment = regexp.MustCompile(`\.([A-Za-z0-9_-]+)|\[(\d+)\]`)
+
+// hasRefs reports whether any string in v contains a reference.
+func hasRefs(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return refPattern.MatchString(v)
+	case map[string]any:
+		for _, item := range v {
+			if hasRefs(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if hasRefs(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveRefs returns a copy of v with references replaced by values from
+// the result entries of earlier calls. A string that is a single reference
+// becomes the referenced value, keeping its type; references within a longer
+// string are replaced by their text. before is the index of the call being
+// resolved, which may only refer to calls before it.
+func resolveRefs(v any, results []map[string]any, before int) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return resolveString(v, results, before)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			resolved, err := resolveRefs(item, results, before)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := resolveRefs(item, results, before)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func resolveString(s string, results []map[string]any, before int) (any, error) {
+	if m := refPattern.FindStringSubmatch(s); m != nil && m[0] == s {
+		return lookupRef(m, results, before)
+	}
+
+	var firstErr error
+	out := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		value, err := lookupRef(refPattern.FindStringSubmatch(ref), results, before)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return ref
+		}
+		if str, ok := value.(string); ok {
+			return str
+		}
+		data, _ := json.Marshal(value)
+		return string(data)
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// lookupRef returns the value a reference match points to.
+func lookupRef(m []string, results []map[string]any, before int) (any, error) {
+	ref := strings.TrimSpace(m[0])
+	idx, err := strconv.Atoi(m[1])
+	if err != nil || idx >= before {
+		return nil, fmt.Errorf("%s: can only refer to an earlier call", ref)
+	}
+	entry := results[idx]
+	if failed(entry) {
+		return nil, fmt.Errorf("%s: call %d did not succeed", ref, idx)
+	}
+
+	var value any = entry
+	for _, seg := range pathSegment.FindAllStringSubmatch(m[2], -1) {
+		switch cur := value.(type) {
+		case map[string]any:
+			if seg[1] == "" {
+				return nil, fmt.Errorf("%s: cannot index an object", ref)
+			}
+			v, ok := cur[seg[1]]
+			if !ok {
+				return nil, fmt.Errorf("%s: no field %q", ref, seg[1])
+			}
+			value = v
+		case []any:
+			if seg[2] == "" {
+				return nil, fmt.Errorf("%s: field %q of an array", ref, seg[1])
+			}
+			i, _ := strconv.Atoi(seg[2])
+			if i >= len(cur) {
+				return nil, fmt.Errorf("%s: index %d out of range (length %d)", ref, i, len(cur))
+			}
+			value = cur[i]
+		default:
+			return nil, fmt.Errorf("%s: cannot look up %q in %T", ref, strings.TrimPrefix(seg[0], "."), value)
+		}
+	}
+	return value, nil
+}
+
+// failed reports whether a batch result entry records a failed or skipped
+// call.
+func failed(entry map[string]any) bool {
+	if entry == nil {
+		return true
+	}
+	if _, ok := entry["error"]; ok {
+		return true
+	}
+	isError, _ := entry["isError"].(bool)
+	return isError
+}
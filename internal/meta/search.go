@@ -0,0 +1,263 @@
+package meta
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/claytono/go-unifi-mcp/internal/cache"
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	"github.com/claytono/go-unifi-mcp/internal/render"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/iancoleman/strcase"
+)
+
+// DefaultSearchLimit is the number of tools tool_index returns for a query
+// without a limit.
+const DefaultSearchLimit = 10
+
+// Weights of the parts of a tool that a query term can match. A term found
+// in several parts adds up their weights.
+const (
+	nameWeight          = 3.0
+	resourceWeight      = 3.0
+	descriptionWeight   = 1.0
+	fieldWeight         = 0.5  // fields of the tool's own input schema
+	resourceFieldWeight = 0.25 // fields of every tool of the same resource
+
+	synonymFactor = 0.8 // for terms a synonym of the query term brings in
+	prefixFactor  = 0.5 // for words the query term is only a prefix of
+)
+
+// synonyms maps query terms, stemmed, to the words the catalog uses for the
+// same thing.
+var synonyms = map[string][]string{
+	"client":    {"user"},
+	"station":   {"user"},
+	"host":      {"user"},
+	"ssid":      {"wlan"},
+	"wifi":      {"wlan"},
+	"wireless":  {"wlan"},
+	"password":  {"passphrase"},
+	"vlan":      {"network"},
+	"subnet":    {"network"},
+	"lan":       {"network"},
+	"wan":       {"network"},
+	"vpn":       {"network"},
+	"ap":        {"device"},
+	"switch":    {"device"},
+	"gateway":   {"device"},
+	"route":     {"routing"},
+	"nat":       {"forward"},
+	"alias":     {"firewallgroup"},
+	"guest":     {"hotspot"},
+	"portal":    {"hotspot", "guest"},
+	"voucher":   {"hotspot"},
+	"ssh":       {"mgmt"},
+	"syslog":    {"rsyslogd"},
+	"firmware":  {"fwupdate"},
+	"upgrade":   {"fwupdate"},
+	"email":     {"smtp", "mail"},
+	"time":      {"ntp"},
+	"led":       {"lighting"},
+	"ids":       {"ips"},
+	"intrusion": {"ips"},
+	"add":       {"create"},
+	"new":       {"create"},
+	"remove":    {"delete"},
+	"edit":      {"update"},
+	"change":    {"update"},
+	"modify":    {"update"},
+	"set":       {"update"},
+	"rename":    {"update"},
+	"enable":    {"update"},
+	"disable":   {"update"},
+	"block":     {"update"},
+	"unblock":   {"update"},
+	"show":      {"list", "get"},
+	"find":      {"list", "get"},
+}
+
+// stopWords carry no meaning in a query.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "for": true, "on": true, "in": true,
+	"my": true, "and": true, "or": true, "with": true, "by": true, "from": true, "is": true, "i": true,
+}
+
+// controlArgs are the schema properties shared by many tools, which say
+// nothing about what a tool is for. They are neither indexed nor shown in
+// example calls.
+var controlArgs = map[string]bool{
+	"site": true, "id": true, controllers.Arg: true, generated.DryRunArg: true,
+	generated.FieldsArg: true, generated.FilterArg: true, generated.LimitArg: true,
+	generated.OffsetArg: true, generated.CursorArg: true, generated.SortArg: true,
+	render.Arg: true, cache.Arg: true,
+}
+
+// categoryRank orders equally scored tools, reads first.
+var categoryRank = map[string]int{"list": 0, "get": 1, "create": 2, "update": 3, "delete": 4}
+
+// searchResult is a tool matched by a query, with its relevance score.
+type searchResult struct {
+	generated.ToolMetadata
+	Score float64
+}
+
+// searchIndex ranks tools against free-text queries. Each tool is indexed by
+// the words of its name, description, resource and input schema fields, and
+// of the fields of its resource's other tools, so list and get tools are
+// found by the fields their resource has. Words are weighted by how few tools
+// have them.
+type searchIndex struct {
+	tools []generated.ToolMetadata
+	terms []map[string]float64 // per tool, the summed weights of each term
+	idf   map[string]float64
+}
+
+func newSearchIndex(tools []generated.ToolMetadata) *searchIndex {
+	fields := func(tool generated.ToolMetadata) []string {
+		props, _ := tool.InputSchema["properties"].(map[string]any)
+		var names []string
+		for name := range props {
+			if !controlArgs[name] {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	resourceFields := make(map[string][]string)
+	for _, tool := range tools {
+		resourceFields[tool.Resource] = append(resourceFields[tool.Resource], fields(tool)...)
+	}
+
+	idx := &searchIndex{tools: tools, terms: make([]map[string]float64, len(tools)), idf: make(map[string]float64)}
+	df := make(map[string]int)
+	for i, tool := range tools {
+		weights := make(map[string]float64)
+		add := func(weight float64, words ...string) {
+			seen := make(map[string]bool)
+			for _, w := range words {
+				for _, term := range tokenize(w) {
+					if !seen[term] {
+						seen[term] = true
+						weights[term] += weight
+					}
+				}
+			}
+		}
+		add(nameWeight, tool.Name)
+		add(resourceWeight, tool.Resource)
+		// The whole name again, so "user" prefers User over UserGroup.
+		add(resourceWeight, strings.ToLower(tool.Resource))
+		add(descriptionWeight, tool.Description)
+		add(fieldWeight, fields(tool)...)
+		add(resourceFieldWeight, resourceFields[tool.Resource]...)
+		idx.terms[i] = weights
+		for term := range weights {
+			df[term]++
+		}
+	}
+	for term, n := range df {
+		idx.idf[term] = math.Log(1 + float64(len(tools))/float64(n))
+	}
+	return idx
+}
+
+// search returns the tools among candidates that match the query, best
+// first, at most limit of them.
+func (idx *searchIndex) search(query string, candidates []generated.ToolMetadata, limit int) []searchResult {
+	allowed := make(map[string]bool, len(candidates))
+	for _, tool := range candidates {
+		allowed[tool.Name] = true
+	}
+	var queryTerms []string
+	seen := make(map[string]bool)
+	for _, term := range tokenize(query) {
+		if !stopWords[term] && !seen[term] {
+			seen[term] = true
+			queryTerms = append(queryTerms, term)
+		}
+	}
+
+	var results []searchResult
+	for i, tool := range idx.tools {
+		if !allowed[tool.Name] {
+			continue
+		}
+		score := 0.0
+		for _, term := range queryTerms {
+			score += idx.match(idx.terms[i], term)
+		}
+		if score > 0 {
+			results = append(results, searchResult{ToolMetadata: tool, Score: math.Round(score*100) / 100})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Category != b.Category {
+			return categoryRank[a.Category] < categoryRank[b.Category]
+		}
+		return a.Name < b.Name
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// match scores one query term against a tool's terms: the best of the term
+// and its synonyms, matched whole or as a prefix.
+func (idx *searchIndex) match(weights map[string]float64, term string) float64 {
+	best := 0.0
+	try := func(candidate string, factor float64) {
+		if w, ok := weights[candidate]; ok {
+			best = max(best, w*factor*idx.idf[candidate])
+		}
+		if len(candidate) < 3 {
+			return
+		}
+		for t, w := range weights {
+			if t != candidate && strings.HasPrefix(t, candidate) {
+				best = max(best, w*factor*prefixFactor*idx.idf[t])
+			}
+		}
+	}
+	try(term, 1)
+	for _, synonym := range synonyms[term] {
+		try(stem(synonym), synonymFactor)
+	}
+	return best
+}
+
+// tokenize splits text into lower-case, stemmed words, breaking identifiers
+// such as PortForward or port_table at word boundaries.
+func tokenize(s string) []string {
+	words := strings.FieldsFunc(strcase.ToSnake(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = stem(w)
+	}
+	return words
+}
+
+// stem strips common plural and -ing endings, so "rules" matches "rule" and
+// "forwarding" matches "forward". Words ending in -as, -is, -ss and -us, such
+// as alias and radius, are kept.
+func stem(w string) string {
+	switch {
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "sses"), strings.HasSuffix(w, "ches"), strings.HasSuffix(w, "shes"), strings.HasSuffix(w, "xes"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "ing") && len(w) > 5:
+		return w[:len(w)-3]
+	case strings.HasSuffix(w, "s") && len(w) > 3 && !strings.ContainsRune("aisu", rune(w[len(w)-2])):
+		return w[:len(w)-1]
+	}
+	return w
+}
@@ -0,0 +1,89 @@
+package meta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/claytono/go-unifi-mcp/internal/journal"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// runTransaction runs the calls of a batch in order until one fails, and
+// then compensates for the calls that succeeded by reversing their changes,
+// newest first: updates are reverted to the state captured before them,
+// created resources are deleted and deleted ones recreated. The result
+// entries of reversed calls are marked rolledBack; if a change cannot be
+// reversed, its call's entry gets a rollbackError instead and the other
+// changes are still reversed.
+func runTransaction(ctx context.Context, client unifi.Client, registry map[string]generated.HandlerFunc,
+	calls []any, batchArgs map[string]any,
+) []map[string]any {
+	results, changes := runSequential(ctx, client, registry, calls, batchArgs, true, &journal.Transaction{})
+	failedAt := -1
+	for idx, result := range results {
+		if failed(result) {
+			failedAt = idx
+			break
+		}
+	}
+	if failedAt < 0 {
+		return results
+	}
+
+	// Compensate even if the batch was canceled, without recording the
+	// inverse calls in the transaction, and without asking for confirmation:
+	// a refused rollback would leave the controller half-configured.
+	ctx = journal.WithRollback(journal.WithTransaction(context.WithoutCancel(ctx), nil))
+	for idx := failedAt - 1; idx >= 0; idx-- {
+		var errs []string
+		for i := len(changes[idx]) - 1; i >= 0; i-- {
+			if err := revert(ctx, client, registry, changes[idx][i]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		switch {
+		case len(errs) > 0:
+			results[idx]["rollbackError"] = strings.Join(errs, "; ")
+		case len(changes[idx]) > 0:
+			results[idx]["rolledBack"] = true
+		}
+	}
+	return results
+}
+
+// revert calls the inverse of a change.
+func revert(ctx context.Context, client unifi.Client, registry map[string]generated.HandlerFunc, change journal.Entry) error {
+	toolName, toolArgs, err := change.Inverse()
+	if err != nil {
+		return err
+	}
+	handlerFactory, ok := registry[toolName]
+	if !ok {
+		return fmt.Errorf("tool %s is not available", toolName)
+	}
+
+	innerReq := mcp.CallToolRequest{}
+	innerReq.Params.Name = toolName
+	innerReq.Params.Arguments = toolArgs
+
+	result, err := handlerFactory(client)(journal.WithUndo(ctx, change.ID), innerReq)
+	if err != nil {
+		return fmt.Errorf("%s: %w", toolName, err)
+	}
+	if result != nil && result.IsError {
+		return fmt.Errorf("%s: %s", toolName, resultText(result))
+	}
+	return nil
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "failed"
+}
@@ -0,0 +1,116 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/claytono/go-unifi-mcp/internal/audit"
+	"github.com/claytono/go-unifi-mcp/internal/journal"
+	"github.com/claytono/go-unifi-mcp/internal/redact"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterJournalTools registers list_changes and undo over the journal. The
+// inverse calls made by undo are dispatched through the given toolset, so
+// they are authorized, confirmed and journaled like any other call. Both
+// tools cover every controller: each change records the controller it was
+// made on and is undone there, so neither takes a controller argument.
+func RegisterJournalTools(s *server.MCPServer, client unifi.Client, tools map[string]generated.HandlerFunc, j *journal.Journal) {
+
+	// list_changes - Lists recent changes that can be undone
+	s.AddTool(mcp.NewTool("list_changes",
+		mcp.WithDescription("Lists the recent create, update and delete calls made through this server, on any controller, newest first. Use the id with undo."),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of changes to return (default: all)")),
+	), ListChangesHandler(j))
+
+	// undo - Reverses a journaled change
+	s.AddTool(mcp.NewTool("undo",
+		mcp.WithDescription("Reverses a change listed by list_changes, on the controller it was made on: restores the previous object of an update, deletes a created resource or recreates a deleted one. Refuses if the resource changed since, unless force is set."),
+		mcp.WithNumber("change_id", mcp.Description("ID of the change to undo (default: the most recent change not yet undone)")),
+		mcp.WithBoolean("force", mcp.Description("Undo even if the resource changed since")),
+		mcp.WithBoolean(generated.DryRunArg, mcp.Description("Preview the undo without applying it")),
+	), UndoHandler(client, tools, j))
+}
+
+// ListChangesHandler returns a handler that lists journaled changes with
+// secrets redacted.
+func ListChangesHandler(j *journal.Journal) server.ToolHandlerFunc {
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		entries := j.List()
+		if limit, ok := req.GetArguments()["limit"].(float64); ok && limit >= 0 && int(limit) < len(entries) {
+			entries = entries[:int(limit)]
+		}
+
+		for i, e := range entries {
+			secrets := redact.SecretsFor(e.Resource)
+			entries[i].Before = redactMap(e.Before, secrets)
+			entries[i].After = redactMap(e.After, secrets)
+		}
+
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError("failed to marshal changes: " + err.Error()), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// UndoHandler returns a handler that reverses a journaled change by calling
+// its inverse tool on the controller the change was made on.
+func UndoHandler(client unifi.Client, registry map[string]generated.HandlerFunc, j *journal.Journal) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		var entry journal.Entry
+		if id, ok := args["change_id"].(float64); ok {
+			if entry, ok = j.Get(int64(id)); !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("change %d not found", int64(id))), nil
+			}
+			if entry.UndoneBy != 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("change %d was already undone by change %d", entry.ID, entry.UndoneBy)), nil
+			}
+		} else if entry, ok = j.Latest(); !ok {
+			return mcp.NewToolResultError("no changes to undo"), nil
+		}
+
+		toolName, toolArgs, err := entry.Inverse()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		handlerFactory, ok := registry[toolName]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("change %d cannot be undone: tool %s is not available", entry.ID, toolName)), nil
+		}
+
+		if force, _ := args["force"].(bool); !force {
+			driftClient := client
+			if entry.Client != nil {
+				driftClient = entry.Client
+			}
+			if err := entry.CheckDrift(ctx, driftClient); err != nil {
+				return mcp.NewToolResultError(err.Error() + "; use force to undo anyway"), nil
+			}
+		}
+		if dryRun, _ := args[generated.DryRunArg].(bool); dryRun {
+			toolArgs[generated.DryRunArg] = true
+		}
+
+		innerReq := mcp.CallToolRequest{}
+		innerReq.Params.Name = toolName
+		innerReq.Params.Arguments = toolArgs
+
+		handler := handlerFactory(client)
+		return handler(journal.WithUndo(ctx, entry.ID), innerReq)
+	}
+}
+
+func redactMap(m map[string]any, secrets map[string]bool) map[string]any {
+	if m == nil {
+		return nil
+	}
+	return audit.Redact(m, secrets).(map[string]any)
+}
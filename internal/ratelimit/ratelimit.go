@@ -0,0 +1,121 @@
+// Package ratelimit limits how fast tool calls reach a controller. Every
+// controller has a token bucket shared by all tool calls sent to it, whether
+// they come from eager tools, execute or batch, so a large batch cannot trip
+// the controller's own rate limits.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Limiter holds a token bucket per controller client. Each bucket starts
+// full with burst tokens and refills at rate tokens per second; every tool
+// call takes one, waiting for it if the bucket is empty.
+type Limiter struct {
+	rate  float64
+	burst int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[unifi.Client]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New returns a limiter allowing rate calls per second per controller with
+// bursts of up to burst calls, at least one.
+func New(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:    rate,
+		burst:   max(burst, 1),
+		now:     time.Now,
+		buckets: make(map[unifi.Client]*bucket),
+	}
+}
+
+// Wait takes a token from the client's bucket, waiting until one is
+// available or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, client unifi.Client) error {
+	delay := l.reserve(client)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel(client)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, letting the bucket go negative, and returns how
+// long the caller must wait for the token to be earned. Reserving in advance
+// serves waiting callers in order.
+func (l *Limiter) reserve(client unifi.Client) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns the token of a caller that gave up waiting.
+func (l *Limiter) cancel(client unifi.Client) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[client]; ok {
+		b.tokens = math.Min(float64(l.burst), b.tokens+1)
+	}
+}
+
+// Middleware makes every tool call wait for a token of its controller before
+// it runs. The time spent waiting is added to the tracker in the call's
+// context, if any.
+func (l *Limiter) Middleware(client unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := l.now()
+		if err := l.Wait(ctx, client); err != nil {
+			return mcp.NewToolResultError("tool " + meta.Name + " was not run: " + err.Error() + " while waiting for the rate limit"), nil
+		}
+		if waited, ok := ctx.Value(waitKey{}).(*atomic.Int64); ok {
+			waited.Add(int64(l.now().Sub(start)))
+		}
+		return next(ctx, req)
+	}
+}
+
+type waitKey struct{}
+
+// TrackWait returns a context that records the time calls made with it spend
+// waiting for the rate limit, and a function reporting the total so far.
+func TrackWait(ctx context.Context) (context.Context, func() time.Duration) {
+	waited := new(atomic.Int64)
+	return context.WithValue(ctx, waitKey{}, waited), func() time.Duration {
+		return time.Duration(waited.Load())
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(2, 3)
+	l.now = func() time.Time { return now }
+	client := servermocks.NewClient(t)
+
+	// The bucket starts full.
+	for range 3 {
+		assert.Zero(t, l.reserve(client))
+	}
+	// Then calls wait for tokens in order, one every half second.
+	assert.Equal(t, 500*time.Millisecond, l.reserve(client))
+	assert.Equal(t, time.Second, l.reserve(client))
+
+	// The bucket refills over time, but never beyond the burst.
+	now = now.Add(time.Hour)
+	for range 3 {
+		assert.Zero(t, l.reserve(client))
+	}
+	assert.Positive(t, l.reserve(client))
+}
+
+func TestLimiter_BucketPerClient(t *testing.T) {
+	l := New(1, 1)
+	home, lab := servermocks.NewClient(t), servermocks.NewClient(t)
+
+	assert.Zero(t, l.reserve(home))
+	assert.Zero(t, l.reserve(lab))
+	assert.Positive(t, l.reserve(home))
+}
+
+func TestLimiter_WaitCanceled(t *testing.T) {
+	l := New(0.001, 1)
+	client := servermocks.NewClient(t)
+	require.NoError(t, l.Wait(context.Background(), client))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(ctx, client), context.DeadlineExceeded)
+
+	// The canceled caller's token is returned.
+	assert.InDelta(t, 0, l.buckets[client].tokens, 0.01)
+}
+
+func TestMiddleware(t *testing.T) {
+	l := New(50, 1)
+	client := servermocks.NewClient(t)
+	meta := generated.ToolMetadata{Name: "list_network", Category: "list"}
+	calls := 0
+	handler := l.Middleware(client, meta, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	ctx, waited := TrackWait(context.Background())
+	for range 3 {
+		result, err := handler(ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+	}
+	assert.Equal(t, 3, calls)
+	// Two of the calls waited about 20ms each.
+	assert.GreaterOrEqual(t, waited(), 30*time.Millisecond)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = handler(context.Background(), mcp.CallToolRequest{})
+	result, err := handler(canceled, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "tool list_network was not run: context canceled")
+	assert.Equal(t, 4, calls)
+}
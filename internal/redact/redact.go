@@ -0,0 +1,216 @@
+// Package redact masks credentials, such as Wi-Fi passphrases, RADIUS
+// secrets and SSH passwords, in tool results before they reach the client.
+// The secret fields of each resource are generated by mcpgen into
+// generated.SecretFields.
+package redact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/claytono/go-unifi-mcp/internal/auth"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Marker replaces the value of a secret field, in tool results and in the
+// audit log alike.
+const Marker = "[REDACTED]"
+
+// Middleware returns a Middleware that masks the secret fields of a tool's
+// resource in its results, unless auth.RevealSecrets allows the caller to see
+// them; reveal applies to callers without an identity. Empty values are kept,
+// so the client can tell whether a secret is set.
+//
+// Clients tend to send back what they read, so a masked value in the
+// arguments of an update leaves the secret unchanged, and elsewhere is
+// refused instead of being written to the controller. A filter or sort on a
+// secret field is refused too, since the rows it selects or orders would
+// give the secret away.
+func Middleware(reveal bool) registry.Middleware {
+	return func(_ unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		secrets := SecretsFor(meta.Resource)
+		if len(secrets) == 0 {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if auth.RevealSecrets(ctx, reveal) {
+				return next(ctx, req)
+			}
+			for _, path := range generated.QueriedFields(req.GetArguments()) {
+				field := path[strings.LastIndex(path, ".")+1:]
+				if secrets[strings.TrimSuffix(field, "[]")] {
+					return mcp.NewToolResultError(fmt.Sprintf(
+						"%s is a secret field and cannot be filtered or sorted on without the %s permission",
+						path, auth.PermissionRevealSecrets)), nil
+				}
+			}
+			if meta.Category == "create" || meta.Category == "update" {
+				args, err := unmask(req.GetArguments(), secrets, meta.Category == "update")
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				req.Params.Arguments = args
+			}
+
+			result, err := next(ctx, req)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			return MaskResult(result, secrets), nil
+		}
+	}
+}
+
+// SecretsFor returns the set of secret fields of a resource.
+func SecretsFor(resource string) map[string]bool {
+	secrets := make(map[string]bool)
+	for _, name := range generated.SecretFields[resource] {
+		secrets[name] = true
+	}
+	return secrets
+}
+
+// unmask removes masked values from the arguments of a write. With keep, a
+// masked top-level field is left out so the update keeps the current value;
+// any other masked value is an error.
+func unmask(args map[string]any, secrets map[string]bool, keep bool) (map[string]any, error) {
+	var out map[string]any
+	for k, v := range args {
+		if secrets[k] && v == Marker && keep {
+			if out == nil {
+				out = make(map[string]any, len(args))
+				for k, v := range args {
+					out[k] = v
+				}
+			}
+			delete(out, k)
+			continue
+		}
+		if path := findMarker(k, v, secrets); path != "" {
+			return nil, fmt.Errorf("%s holds the masked value %s; pass the real value or leave the field out", path, Marker)
+		}
+	}
+	if out == nil {
+		return args, nil
+	}
+	return out, nil
+}
+
+// findMarker returns the path of a secret field below name whose value is
+// Marker, or "" if there is none.
+func findMarker(name string, v any, secrets map[string]bool) string {
+	switch v := v.(type) {
+	case string:
+		if secrets[name] && v == Marker {
+			return name
+		}
+	case map[string]any:
+		for k, field := range v {
+			if path := findMarker(k, field, secrets); path != "" {
+				return name + "." + path
+			}
+		}
+	case []any:
+		for i, item := range v {
+			elem := fmt.Sprintf("%s[%d]", name, i)
+			switch item := item.(type) {
+			case string:
+				if secrets[name] && item == Marker {
+					return elem
+				}
+			case map[string]any:
+				for k, field := range item {
+					if path := findMarker(k, field, secrets); path != "" {
+						return elem + "." + path
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// MaskResult masks the secret fields in the JSON text content of a result.
+// Content that is not JSON, or holds no secrets, is left as it is.
+func MaskResult(result *mcp.CallToolResult, secrets map[string]bool) *mcp.CallToolResult {
+	out := *result
+	out.Content = make([]mcp.Content, len(result.Content))
+	for i, content := range result.Content {
+		out.Content[i] = content
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(text.Text))
+		dec.UseNumber()
+		var decoded any
+		if dec.Decode(&decoded) != nil {
+			continue
+		}
+		if !Mask(decoded, secrets) {
+			continue
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if enc.Encode(decoded) != nil {
+			continue
+		}
+		text.Text = strings.TrimSuffix(buf.String(), "\n")
+		out.Content[i] = text
+	}
+	return &out
+}
+
+// Mask replaces the non-empty values of secret fields in a decoded JSON
+// value with Marker, in place, at any depth. The before and after values of
+// a dry run's change to a secret field are masked too. It reports whether
+// anything was masked.
+func Mask(v any, secrets map[string]bool) bool {
+	masked := false
+	switch v := v.(type) {
+	case map[string]any:
+		if field, ok := v["field"].(string); ok && secrets[field[strings.LastIndex(field, ".")+1:]] {
+			for _, k := range []string{"before", "after"} {
+				if set(v[k]) {
+					v[k] = Marker
+					masked = true
+				}
+			}
+		}
+		for k, field := range v {
+			if secrets[k] && set(field) {
+				v[k] = Marker
+				masked = true
+				continue
+			}
+			masked = Mask(field, secrets) || masked
+		}
+	case []any:
+		for _, item := range v {
+			masked = Mask(item, secrets) || masked
+		}
+	}
+	return masked
+}
+
+// set reports whether a secret field has a value worth masking.
+func set(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case string:
+		return v != "" && v != Marker
+	case []any:
+		return len(v) > 0
+	}
+	return true
+}
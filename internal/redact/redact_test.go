@@ -0,0 +1,162 @@
+package redact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/claytono/go-unifi-mcp/internal/auth"
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	getWLAN    = generated.ToolMetadata{Name: "get_wlan", Category: "get", Resource: "WLAN"}
+	updateWLAN = generated.ToolMetadata{Name: "update_wlan", Category: "update", Resource: "WLAN"}
+	createWLAN = generated.ToolMetadata{Name: "create_wlan", Category: "create", Resource: "WLAN"}
+	listRADIUS = generated.ToolMetadata{Name: "list_radius_profile", Category: "list", Resource: "RADIUSProfile"}
+	listTag    = generated.ToolMetadata{Name: "list_tag", Category: "list", Resource: "Tag"}
+)
+
+// reply returns a handler that records its arguments and returns text.
+func reply(text string, seen *map[string]any) server.ToolHandlerFunc {
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if seen != nil {
+			*seen = req.GetArguments()
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+func call(t *testing.T, ctx context.Context, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handler(ctx, req)
+	require.NoError(t, err)
+	return result
+}
+
+func text(result *mcp.CallToolResult) string {
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestMiddleware_MasksSecrets(t *testing.T) {
+	client := servermocks.NewClient(t)
+	wlan := `{"name": "Home", "x_passphrase": "hunter22", "x_iapp_key": "", "vlan": 12345678901234567890}`
+	handler := Middleware(false)(client, getWLAN, reply(wlan, nil))
+
+	result := call(t, context.Background(), handler, map[string]any{"id": "w1"})
+	assert.JSONEq(t, `{"name": "Home", "x_passphrase": "[REDACTED]", "x_iapp_key": "", "vlan": 12345678901234567890}`, text(result))
+
+	// Secrets nested in arrays are masked as well.
+	profiles := `[{"name": "corp", "auth_servers": [{"ip": "10.0.0.5", "x_secret": "s3cret"}]}]`
+	result = call(t, context.Background(), Middleware(false)(client, listRADIUS, reply(profiles, nil)), nil)
+	assert.JSONEq(t, `[{"name": "corp", "auth_servers": [{"ip": "10.0.0.5", "x_secret": "[REDACTED]"}]}]`, text(result))
+
+	// Results without secrets, and resources without secret fields, are
+	// left as they are.
+	plain := `{"name": "Home",   "x_passphrase": ""}`
+	assert.Equal(t, plain, text(call(t, context.Background(), Middleware(false)(client, getWLAN, reply(plain, nil)), nil)))
+	tags := `[{"name": "x_secret", "x_secret": "not a credential of tags"}]`
+	assert.Equal(t, tags, text(call(t, context.Background(), Middleware(false)(client, listTag, reply(tags, nil)), nil)))
+}
+
+func TestMiddleware_Reveal(t *testing.T) {
+	client := servermocks.NewClient(t)
+	wlan := `{"x_passphrase": "hunter22"}`
+
+	// reveal applies to callers without an identity.
+	assert.Equal(t, wlan, text(call(t, context.Background(), Middleware(true)(client, getWLAN, reply(wlan, nil)), nil)))
+
+	// Token callers need the permission.
+	viewer := auth.WithIdentity(context.Background(), auth.Identity{Name: "oncall", Role: auth.RoleViewer})
+	assert.Contains(t, text(call(t, viewer, Middleware(true)(client, getWLAN, reply(wlan, nil)), nil)), Marker)
+	granted := auth.WithIdentity(context.Background(), auth.Identity{
+		Name: "wifi", Role: auth.RoleViewer, Permissions: []string{auth.PermissionRevealSecrets},
+	})
+	assert.Equal(t, wlan, text(call(t, granted, Middleware(false)(client, getWLAN, reply(wlan, nil)), nil)))
+}
+
+func TestMiddleware_RefusesQueriesOnSecrets(t *testing.T) {
+	client := servermocks.NewClient(t)
+	listWLAN := generated.ToolMetadata{Name: "list_wlan", Category: "list", Resource: "WLAN"}
+	wlans := `[{"name": "Home", "x_passphrase": "hunter22"}]`
+
+	for _, args := range []map[string]any{
+		{generated.FilterArg: `x_passphrase =~ "^a"`},
+		{generated.FilterArg: `name == "Home" && x_passphrase contains "hunter"`},
+		{generated.FilterArg: `x_passphrase > "m"`},
+		{generated.FilterArg: `!(x_passphrase == "hunter22")`},
+		{generated.SortArg: "-x_passphrase"},
+	} {
+		var seen map[string]any
+		result := call(t, context.Background(), Middleware(false)(client, listWLAN, reply(wlans, &seen)), args)
+		assert.True(t, result.IsError, args)
+		assert.Contains(t, text(result), "x_passphrase is a secret field")
+		assert.Nil(t, seen, "the list is not run")
+	}
+
+	// Nested secrets are found by the last segment of the path.
+	result := call(t, context.Background(), Middleware(false)(client, listRADIUS, reply(`[]`, nil)), map[string]any{
+		generated.FilterArg: `auth_servers[].x_secret =~ "^s"`,
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "auth_servers[].x_secret is a secret field")
+
+	// Other fields, and callers allowed to see secrets, are not restricted.
+	args := map[string]any{generated.FilterArg: `name == "Home"`, generated.SortArg: "name"}
+	assert.False(t, call(t, context.Background(), Middleware(false)(client, listWLAN, reply(wlans, nil)), args).IsError)
+	args = map[string]any{generated.FilterArg: `x_passphrase =~ "^h"`, generated.SortArg: "x_passphrase"}
+	assert.Equal(t, wlans, text(call(t, context.Background(), Middleware(true)(client, listWLAN, reply(wlans, nil)), args)))
+}
+
+func TestMiddleware_MaskedArguments(t *testing.T) {
+	client := servermocks.NewClient(t)
+	var seen map[string]any
+	update := Middleware(false)(client, updateWLAN, reply(`{"x_passphrase": "hunter22"}`, &seen))
+
+	// An update that echoes a masked secret keeps the current value.
+	result := call(t, context.Background(), update, map[string]any{"id": "w1", "name": "Home", "x_passphrase": Marker})
+	require.False(t, result.IsError, text(result))
+	assert.Equal(t, map[string]any{"id": "w1", "name": "Home"}, seen)
+	assert.Contains(t, text(result), Marker, "the result is masked")
+
+	result = call(t, context.Background(), update, map[string]any{"id": "w1", "x_passphrase": "correct horse"})
+	require.False(t, result.IsError)
+	assert.Equal(t, "correct horse", seen["x_passphrase"])
+
+	// Masked values elsewhere would be written to the controller.
+	seen = nil
+	create := Middleware(false)(client, createWLAN, reply(`{}`, &seen))
+	result = call(t, context.Background(), create, map[string]any{"name": "Home", "x_passphrase": Marker})
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "x_passphrase holds the masked value")
+	assert.Nil(t, seen)
+
+	updateRADIUS := Middleware(false)(client, generated.ToolMetadata{Name: "update_radius_profile", Category: "update", Resource: "RADIUSProfile"}, reply(`{}`, &seen))
+	result = call(t, context.Background(), updateRADIUS, map[string]any{
+		"id":           "r1",
+		"auth_servers": []any{map[string]any{"ip": "10.0.0.5", "x_secret": Marker}},
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "auth_servers[0].x_secret holds the masked value")
+}
+
+func TestMask_DryRunChanges(t *testing.T) {
+	plan := map[string]any{
+		"dry_run": true,
+		"changes": []any{
+			map[string]any{"field": "name", "before": "Home", "after": "House"},
+			map[string]any{"field": "x_passphrase", "before": "hunter22", "after": "correct horse"},
+		},
+	}
+	assert.True(t, Mask(plan, map[string]bool{"x_passphrase": true}))
+	assert.Equal(t, []any{
+		map[string]any{"field": "name", "before": "Home", "after": "House"},
+		map[string]any{"field": "x_passphrase", "before": Marker, "after": Marker},
+	}, plan["changes"])
+}
@@ -0,0 +1,422 @@
+// Package render formats tool results as compact or indented JSON, YAML,
+// CSV or a Markdown table. Tools produce JSON; the format argument, or the
+// server's default format, selects how it is presented to the client.
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// Arg is the tool argument that selects the output format of a call.
+const Arg = "format"
+
+// Format is an output format.
+type Format string
+
+// Output formats.
+const (
+	JSON       Format = "json"        // compact JSON
+	JSONIndent Format = "json-indent" // JSON indented by two spaces
+	YAML       Format = "yaml"
+	CSV        Format = "csv"      // one row per object, see Marshal
+	Markdown   Format = "markdown" // a table with one row per object
+)
+
+// Default is the output format when none is configured.
+const Default = JSONIndent
+
+// Formats lists the output formats.
+var Formats = []Format{JSON, JSONIndent, YAML, CSV, Markdown}
+
+// ArgSchema is the JSON schema of the format argument.
+var ArgSchema = map[string]any{
+	"type":        "string",
+	"enum":        []any{string(JSON), string(JSONIndent), string(YAML), string(CSV), string(Markdown)},
+	"description": "Output format; csv and markdown tables have a column per entry of fields (default: the server's format)",
+}
+
+// Parse returns the format with the given name. An empty name is Default.
+func Parse(s string) (Format, error) {
+	if s == "" {
+		return Default, nil
+	}
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown format %q: must be one of %s", s, strings.Join(names, ", "))
+}
+
+// FromArgs returns the format requested with the format argument, or
+// fallback if there is none.
+func FromArgs(args map[string]any, fallback Format) (Format, error) {
+	raw, ok := args[Arg]
+	if !ok || raw == nil || raw == "" {
+		return fallback, nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", Arg)
+	}
+	return Parse(s)
+}
+
+type defaultKey struct{}
+
+// WithDefault overrides the server's default format for the calls made with
+// ctx, such as the calls of a batch, whose results must be JSON.
+func WithDefault(ctx context.Context, format Format) context.Context {
+	return context.WithValue(ctx, defaultKey{}, format)
+}
+
+func defaultFromContext(ctx context.Context, fallback Format) Format {
+	if format, ok := ctx.Value(defaultKey{}).(Format); ok {
+		return format
+	}
+	return fallback
+}
+
+// Middleware returns a Middleware that renders the JSON results of tools in
+// the format requested by the call, or in defaultFormat. The format argument
+// is removed before the tool runs, so the layers after this one see JSON.
+// Table formats take their columns from the fields argument.
+func Middleware(defaultFormat Format) registry.Middleware {
+	return func(_ unifi.Client, _ generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.GetArguments()
+			format, err := FromArgs(args, defaultFromContext(ctx, defaultFormat))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if _, ok := args[Arg]; ok {
+				newArgs := make(map[string]any, len(args))
+				for k, v := range args {
+					if k != Arg {
+						newArgs[k] = v
+					}
+				}
+				req.Params.Arguments = newArgs
+			}
+
+			result, err := next(ctx, req)
+			if err != nil || result == nil || result.IsError || format == JSONIndent {
+				return result, err
+			}
+			return Convert(result, format, columns(args)), nil
+		}
+	}
+}
+
+// columns returns the paths of the fields argument, in order.
+func columns(args map[string]any) []string {
+	var cols []string
+	switch v := args[generated.FieldsArg].(type) {
+	case []string:
+		cols = v
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				cols = append(cols, strings.TrimSpace(s))
+			}
+		}
+	}
+	return cols
+}
+
+// Convert re-renders the JSON text content of a result in the format. Content
+// that is not JSON is left as it is.
+func Convert(result *mcp.CallToolResult, format Format, columns []string) *mcp.CallToolResult {
+	out := *result
+	out.Content = make([]mcp.Content, len(result.Content))
+	for i, content := range result.Content {
+		out.Content[i] = content
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if converted, err := convertText(text.Text, format, columns); err == nil {
+			text.Text = converted
+			out.Content[i] = text
+		}
+	}
+	return &out
+}
+
+func convertText(text string, format Format, columns []string) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case JSON:
+		// Compact keeps the order of the fields.
+		if err := json.Compact(&buf, []byte(text)); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	case JSONIndent:
+		if err := json.Indent(&buf, []byte(text), "", "  "); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		return "", err
+	}
+	return marshalDecoded(decoded, format, columns)
+}
+
+// Marshal renders a value in the format. CSV and Markdown render an array as
+// one row per element, with a column per entry of columns, or per field of
+// the objects if columns is empty. An object with an items array, such as a
+// page of a list, renders its items as rows and its other fields as a
+// summary line after the table; any other object is a single row.
+func Marshal(v any, format Format, columns []string) (string, error) {
+	switch format {
+	case JSON:
+		data, err := json.Marshal(v)
+		return string(data), err
+	case JSONIndent:
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return "", err
+	}
+	return marshalDecoded(decoded, format, columns)
+}
+
+// Result renders a value in the format as a tool result.
+func Result(v any, format Format, columns []string) (*mcp.CallToolResult, error) {
+	text, err := Marshal(v, format, columns)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %v", err)), nil
+	}
+	return mcp.NewToolResultText(text), nil
+}
+
+func marshalDecoded(v any, format Format, columns []string) (string, error) {
+	switch format {
+	case YAML:
+		data, err := yaml.Marshal(v)
+		return string(data), err
+	case CSV, Markdown:
+		header, rows, summary := table(v, columns)
+		if format == CSV {
+			return writeCSV(header, rows, summary)
+		}
+		return writeMarkdown(header, rows, summary), nil
+	}
+	return "", fmt.Errorf("unknown format %q", format)
+}
+
+// table flattens a decoded value into a header and rows, see Marshal.
+func table(v any, columns []string) (header []string, rows [][]string, summary string) {
+	var items []any
+	switch v := v.(type) {
+	case []any:
+		items = v
+	case map[string]any:
+		if list, ok := v["items"].([]any); ok {
+			items = list
+			summary = summarize(v)
+		} else {
+			items = []any{v}
+		}
+	default:
+		items = []any{v}
+	}
+
+	header = columns
+	if len(header) == 0 {
+		header = objectKeys(items)
+	}
+	if len(header) == 0 {
+		header = []string{valueColumn}
+	}
+	rows = make([][]string, len(items))
+	for i, item := range items {
+		row := make([]string, len(header))
+		obj, isObj := item.(map[string]any)
+		for j, col := range header {
+			if col == valueColumn && !isObj {
+				row[j] = cell(item)
+			} else {
+				row[j] = cell(lookup(obj, col))
+			}
+		}
+		rows[i] = row
+	}
+	return header, rows, summary
+}
+
+// valueColumn heads the column of array elements that are not objects.
+const valueColumn = "value"
+
+// objectKeys returns the sorted keys of the objects among items, and a
+// value column if any item is not an object.
+func objectKeys(items []any) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	hasValues := false
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			hasValues = true
+			continue
+		}
+		for k := range obj {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	if hasValues && !seen[valueColumn] {
+		keys = append([]string{valueColumn}, keys...)
+	}
+	return keys
+}
+
+// lookup returns the values at a field path, as written for the fields
+// argument. A path through an array marked with [] collects the values of
+// every element.
+func lookup(v any, path string) any {
+	values := []any{v}
+	for _, seg := range strings.Split(path, ".") {
+		name := strings.TrimSuffix(seg, "[]")
+		var next []any
+		for _, value := range values {
+			obj, _ := value.(map[string]any)
+			field, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if list, isList := field.([]any); isList && name != seg {
+				next = append(next, list...)
+			} else {
+				next = append(next, field)
+			}
+		}
+		values = next
+	}
+	switch {
+	case len(values) == 0:
+		return nil
+	case len(values) == 1 && !strings.Contains(path, "[]"):
+		return values[0]
+	}
+	return values
+}
+
+// cell renders one value of a table.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, len(v))
+		scalars := true
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				scalars = false
+			}
+			parts[i] = cell(item)
+		}
+		if scalars {
+			return strings.Join(parts, ", ")
+		}
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// summarize describes the fields of a page other than its items.
+func summarize(page map[string]any) string {
+	var keys []string
+	for k := range page {
+		if k != "items" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + cell(page[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeCSV writes the table as CSV. The summary follows as a comment line.
+func writeCSV(header []string, rows [][]string, summary string) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	if summary != "" {
+		buf.WriteString("# " + summary + "\n")
+	}
+	return buf.String(), nil
+}
+
+// writeMarkdown writes the table as a Markdown table, followed by the
+// summary.
+func writeMarkdown(header []string, rows [][]string, summary string) string {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, c := range cells {
+			c = strings.ReplaceAll(c, "|", `\|`)
+			c = strings.ReplaceAll(c, "\n", " ")
+			b.WriteString(" " + c + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(header)
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	if summary != "" {
+		b.WriteString("\n" + summary + "\n")
+	}
+	return b.String()
+}
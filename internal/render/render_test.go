@@ -0,0 +1,121 @@
+package render
+
+import (
+	"context"
+	"testing"
+
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const networks = `[
+  {"_id": "n1", "name": "LAN", "vlan": 10, "tags": ["home", "wired"]},
+  {"_id": "n2", "name": "Guest|IoT", "enabled": false}
+]`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("")
+	require.NoError(t, err)
+	assert.Equal(t, Default, f)
+
+	f, err = Parse("YAML")
+	require.NoError(t, err)
+	assert.Equal(t, YAML, f)
+
+	_, err = Parse("xml")
+	assert.ErrorContains(t, err, `unknown format "xml": must be one of json, json-indent, yaml, csv, markdown`)
+
+	_, err = FromArgs(map[string]any{Arg: 1}, JSON)
+	assert.ErrorContains(t, err, "format must be a string")
+	f, err = FromArgs(map[string]any{}, CSV)
+	require.NoError(t, err)
+	assert.Equal(t, CSV, f)
+}
+
+func TestMarshal(t *testing.T) {
+	v := []map[string]any{{"name": "LAN", "vlan": 10}}
+
+	out, err := Marshal(v, JSON, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"LAN","vlan":10}]`, out)
+
+	out, err = Marshal(v, JSONIndent, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[\n  {\n    \"name\": \"LAN\",\n    \"vlan\": 10\n  }\n]", out)
+
+	out, err = Marshal(v, YAML, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "- name: LAN\n  vlan: 10\n", out)
+
+	out, err = Marshal(v, CSV, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "name,vlan\nLAN,10\n", out)
+
+	_, err = Marshal(v, Format("xml"), nil)
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+}
+
+func TestConvert_Tables(t *testing.T) {
+	result := mcp.NewToolResultText(networks)
+
+	text := func(r *mcp.CallToolResult) string { return r.Content[0].(mcp.TextContent).Text }
+	assert.Equal(t, "_id,enabled,name,tags,vlan\n"+
+		"n1,,LAN,\"home, wired\",10\n"+
+		"n2,false,Guest|IoT,,\n", text(Convert(result, CSV, nil)), "columns default to every key")
+
+	assert.Equal(t, "| name | vlan |\n"+
+		"| --- | --- |\n"+
+		"| LAN | 10 |\n"+
+		"| Guest\\|IoT |  |\n", text(Convert(result, Markdown, []string{"name", "vlan"})))
+	assert.Equal(t, networks, text(result), "the original result is unchanged")
+
+	// A page renders its items, with the rest as a summary.
+	page := mcp.NewToolResultText(`{"items": [{"name": "LAN"}], "total": 2, "offset": 0, "next_cursor": "abc"}`)
+	assert.Equal(t, "name\nLAN\n# next_cursor: abc, offset: 0, total: 2\n", text(Convert(page, CSV, nil)))
+	assert.Equal(t, "| name |\n| --- |\n| LAN |\n\nnext_cursor: abc, offset: 0, total: 2\n", text(Convert(page, Markdown, nil)))
+
+	// Paths through arrays collect every element.
+	device := mcp.NewToolResultText(`{"name": "switch", "port_table": [{"port_idx": 1}, {"port_idx": 2}]}`)
+	assert.Equal(t, "name,port_table[].port_idx\nswitch,\"1, 2\"\n",
+		text(Convert(device, CSV, []string{"name", "port_table[].port_idx"})))
+
+	// Text that is not JSON is left alone.
+	plain := mcp.NewToolResultText("deleted")
+	assert.Equal(t, "deleted", text(Convert(plain, YAML, nil)))
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen map[string]any
+	next := func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = req.GetArguments()
+		return mcp.NewToolResultText(`[{"name": "LAN"}]`), nil
+	}
+	handler := Middleware(YAML)(servermocks.NewClient(t), generated.ToolMetadata{Name: "list_network"}, next)
+
+	call := func(ctx context.Context, args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handler(ctx, req)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(context.Background(), map[string]any{"site": "default"})
+	assert.Equal(t, "- name: LAN\n", result.Content[0].(mcp.TextContent).Text, "the default format applies")
+
+	result = call(context.Background(), map[string]any{"site": "default", Arg: "json"})
+	assert.Equal(t, `[{"name":"LAN"}]`, result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, map[string]any{"site": "default"}, seen, "the format is not passed on")
+
+	result = call(WithDefault(context.Background(), JSON), nil)
+	assert.Equal(t, `[{"name":"LAN"}]`, result.Content[0].(mcp.TextContent).Text, "the context overrides the default")
+
+	result = call(WithDefault(context.Background(), JSON), map[string]any{Arg: "csv", generated.FieldsArg: []any{"name"}})
+	assert.Equal(t, "name\nLAN\n", result.Content[0].(mcp.TextContent).Text, "the argument overrides the context")
+
+	result = call(context.Background(), map[string]any{Arg: "xml"})
+	assert.True(t, result.IsError)
+}
@@ -3,12 +3,21 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/claytono/go-unifi-mcp/internal/audit"
+	"github.com/claytono/go-unifi-mcp/internal/auth"
+	"github.com/claytono/go-unifi-mcp/internal/journal"
 	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
 	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
 	"github.com/filipowm/go-unifi/unifi"
 	clientpkg "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -55,7 +64,7 @@ func TestLazyModeEndToEnd(t *testing.T) {
 	toolList, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
 	require.NoError(t, err)
 	require.NotNil(t, toolList)
-	assert.Len(t, toolList.Tools, 3)
+	assert.Len(t, toolList.Tools, 4)
 
 	// Fetch the generated tool catalog via the meta tool.
 	indexRequest := mcp.CallToolRequest{}
@@ -166,3 +175,653 @@ func TestEagerModeEndToEnd(t *testing.T) {
 
 	client.AssertExpectations(t)
 }
+
+func TestHTTPTransportEndToEnd(t *testing.T) {
+	ctx := context.Background()
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeLazy})
+	require.NoError(t, err)
+
+	// Serve on a random local port and stop the server when the test ends.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serveCtx, stop := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(serveCtx, s, ln, nil)
+	}()
+
+	mcpClient, err := clientpkg.NewStreamableHttpClient("http://" + ln.Addr().String() + HTTPEndpoint)
+	require.NoError(t, err)
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	toolList, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, toolList.Tools, 4)
+
+	executeRequest := mcp.CallToolRequest{}
+	executeRequest.Params.Name = "execute"
+	executeRequest.Params.Arguments = map[string]any{
+		"tool":      "list_network",
+		"arguments": map[string]any{},
+	}
+	executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+	require.NoError(t, err)
+	assert.False(t, executeResult.IsError)
+
+	require.NoError(t, mcpClient.Close())
+	stop()
+	require.NoError(t, <-served)
+
+	client.AssertExpectations(t)
+}
+
+func TestHTTPTransportAuthorization(t *testing.T) {
+	ctx := context.Background()
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeLazy})
+	require.NoError(t, err)
+
+	tokens, err := auth.NewTokenStore([]auth.Token{
+		{Name: "oncall", Token: "viewer-token", Role: auth.RoleViewer},
+	})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serveCtx, stop := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(serveCtx, s, ln, tokens)
+	}()
+	url := "http://" + ln.Addr().String() + HTTPEndpoint
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+
+	// Requests without a token are rejected before reaching the MCP server.
+	anonymous, err := clientpkg.NewStreamableHttpClient(url)
+	require.NoError(t, err)
+	require.NoError(t, anonymous.Start(ctx))
+	_, err = anonymous.Initialize(ctx, initRequest)
+	require.Error(t, err)
+	require.NoError(t, anonymous.Close())
+
+	mcpClient, err := clientpkg.NewStreamableHttpClient(url,
+		transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer viewer-token"}))
+	require.NoError(t, err)
+	require.NoError(t, mcpClient.Start(ctx))
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	// Viewers may read.
+	listRequest := mcp.CallToolRequest{}
+	listRequest.Params.Name = "execute"
+	listRequest.Params.Arguments = map[string]any{"tool": "list_network"}
+	listResult, err := mcpClient.CallTool(ctx, listRequest)
+	require.NoError(t, err)
+	assert.False(t, listResult.IsError)
+
+	// Viewers may not delete; the controller is never called.
+	deleteRequest := mcp.CallToolRequest{}
+	deleteRequest.Params.Name = "execute"
+	deleteRequest.Params.Arguments = map[string]any{
+		"tool":      "delete_network",
+		"arguments": map[string]any{"id": "abc"},
+	}
+	deleteResult, err := mcpClient.CallTool(ctx, deleteRequest)
+	require.NoError(t, err)
+	assert.True(t, deleteResult.IsError)
+	deleteContent, ok := deleteResult.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, deleteContent.Text, `"delete:Network"`)
+
+	require.NoError(t, mcpClient.Close())
+	stop()
+	require.NoError(t, <-served)
+
+	client.AssertExpectations(t)
+}
+
+func TestConfiguredSiteEndToEnd(t *testing.T) {
+	ctx := context.Background()
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return([]unifi.Site{
+		{Name: "default", Description: "Default"},
+		{Name: "k2m4x9qa", Description: "Branch Office"},
+	}, nil).Once()
+	client.On("ListNetwork", mock.Anything, "k2m4x9qa").Return([]unifi.Network{}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeLazy, Site: "Branch Office", AllowedSites: []string{"Branch Office"}})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	// Calls without a site use the configured site, resolved to its short name.
+	executeRequest := mcp.CallToolRequest{}
+	executeRequest.Params.Name = "execute"
+	executeRequest.Params.Arguments = map[string]any{"tool": "list_network"}
+	executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+	require.NoError(t, err)
+	assert.False(t, executeResult.IsError)
+
+	// Calls for other sites are rejected inside batch as well.
+	batchRequest := mcp.CallToolRequest{}
+	batchRequest.Params.Name = "batch"
+	batchRequest.Params.Arguments = map[string]any{
+		"calls": []any{
+			map[string]any{"tool": "list_network", "arguments": map[string]any{"site": "default"}},
+		},
+	}
+	batchResult, err := mcpClient.CallTool(ctx, batchRequest)
+	require.NoError(t, err)
+	batchContent, ok := batchResult.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, batchContent.Text, "is not allowed")
+
+	client.AssertExpectations(t)
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	ctx := context.Background()
+
+	// Eager mode only registers list and get tools.
+	eager, err := New(Options{Client: servermocks.NewClient(t), Mode: ModeEager, ReadOnly: true})
+	require.NoError(t, err)
+	readOnlyCount := 0
+	for _, meta := range generated.AllToolMetadata {
+		if meta.Category == "list" || meta.Category == "get" {
+			readOnlyCount++
+		}
+	}
+	assert.Len(t, eager.ListTools(), readOnlyCount)
+	assert.NotContains(t, eager.ListTools(), "delete_network")
+
+	// Lazy mode hides mutating tools from the index and refuses to run them.
+	client := servermocks.NewClient(t)
+	s, err := New(Options{Client: client, Mode: ModeLazy, ReadOnly: true})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	indexRequest := mcp.CallToolRequest{}
+	indexRequest.Params.Name = "tool_index"
+	indexRequest.Params.Arguments = map[string]any{}
+	indexResult, err := mcpClient.CallTool(ctx, indexRequest)
+	require.NoError(t, err)
+	var toolCatalog []generated.ToolMetadata
+	err = json.Unmarshal([]byte(indexResult.Content[0].(mcp.TextContent).Text), &toolCatalog)
+	require.NoError(t, err)
+	assert.Len(t, toolCatalog, readOnlyCount)
+
+	executeRequest := mcp.CallToolRequest{}
+	executeRequest.Params.Name = "execute"
+	executeRequest.Params.Arguments = map[string]any{
+		"tool":      "delete_network",
+		"arguments": map[string]any{"id": "abc"},
+	}
+	executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+	require.NoError(t, err)
+	assert.True(t, executeResult.IsError)
+	assert.Contains(t, executeResult.Content[0].(mcp.TextContent).Text, "read-only mode")
+
+	client.AssertExpectations(t)
+}
+
+func TestToolFilterLazyMode(t *testing.T) {
+	ctx := context.Background()
+	client := servermocks.NewClient(t)
+
+	s, err := New(Options{Client: client, Mode: ModeLazy, ToolsExclude: []string{"resource:Hotspot*"}})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	// Hidden tools are absent from the index.
+	indexRequest := mcp.CallToolRequest{}
+	indexRequest.Params.Name = "tool_index"
+	indexRequest.Params.Arguments = map[string]any{"resource": "hotspot"}
+	indexResult, err := mcpClient.CallTool(ctx, indexRequest)
+	require.NoError(t, err)
+	var toolCatalog []generated.ToolMetadata
+	err = json.Unmarshal([]byte(indexResult.Content[0].(mcp.TextContent).Text), &toolCatalog)
+	require.NoError(t, err)
+	assert.Empty(t, toolCatalog)
+
+	// And cannot be called through execute or batch.
+	executeRequest := mcp.CallToolRequest{}
+	executeRequest.Params.Name = "execute"
+	executeRequest.Params.Arguments = map[string]any{"tool": "list_hotspot_op"}
+	executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+	require.NoError(t, err)
+	assert.True(t, executeResult.IsError)
+	assert.Contains(t, executeResult.Content[0].(mcp.TextContent).Text, "unknown tool")
+
+	batchRequest := mcp.CallToolRequest{}
+	batchRequest.Params.Name = "batch"
+	batchRequest.Params.Arguments = map[string]any{
+		"calls": []any{map[string]any{"tool": "list_hotspot_op"}},
+	}
+	batchResult, err := mcpClient.CallTool(ctx, batchRequest)
+	require.NoError(t, err)
+	assert.Contains(t, batchResult.Content[0].(mcp.TextContent).Text, "unknown tool")
+}
+
+func TestDryRunMode(t *testing.T) {
+	ctx := context.Background()
+	client := servermocks.NewClient(t)
+	// Only the lookup is expected; the strict mock fails if DeleteNetwork runs.
+	client.On("GetNetwork", mock.Anything, "default", "abc").Return(&unifi.Network{ID: "abc", Name: "Guest"}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeLazy, DryRun: true})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	// dry_run: false from the caller cannot override the server setting.
+	executeRequest := mcp.CallToolRequest{}
+	executeRequest.Params.Name = "execute"
+	executeRequest.Params.Arguments = map[string]any{
+		"tool":      "delete_network",
+		"arguments": map[string]any{"id": "abc", "dry_run": false},
+	}
+	executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+	require.NoError(t, err)
+	require.False(t, executeResult.IsError)
+
+	var plan generated.DryRunPlan
+	err = json.Unmarshal([]byte(executeResult.Content[0].(mcp.TextContent).Text), &plan)
+	require.NoError(t, err)
+	assert.True(t, plan.DryRun)
+	assert.Equal(t, "delete", plan.Action)
+	assert.Equal(t, "Network", plan.Resource)
+	assert.Equal(t, "abc", plan.ID)
+
+	client.AssertExpectations(t)
+}
+
+// approver answers every elicitation request with a fixed confirmation.
+type approver struct {
+	approve bool
+}
+
+func (a approver) Elicit(_ context.Context, _ mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+		Action:  mcp.ElicitationResponseActionAccept,
+		Content: map[string]any{"confirm": a.approve},
+	}}, nil
+}
+
+func TestConfirmationEndToEnd(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		handler  *approver // nil for a client without elicitation support
+		applied  bool
+		expected string
+	}{
+		{name: "no elicitation support", expected: "does not support elicitation"},
+		{name: "rejected", handler: &approver{approve: false}, expected: "not approved"},
+		{name: "approved", handler: &approver{approve: true}, applied: true, expected: "success"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := servermocks.NewClient(t)
+			client.On("GetNetwork", mock.Anything, "default", "abc").Return(&unifi.Network{ID: "abc", Name: "Guest"}, nil).Once()
+			if tc.applied {
+				client.On("DeleteNetwork", mock.Anything, "default", "abc").Return(nil).Once()
+			}
+
+			s, err := New(Options{Client: client, Mode: ModeLazy, ConfirmTools: []string{"category:delete"}})
+			require.NoError(t, err)
+
+			var mcpClient *clientpkg.Client
+			if tc.handler == nil {
+				mcpClient, err = clientpkg.NewInProcessClient(s)
+				require.NoError(t, err)
+			} else {
+				mcpClient = clientpkg.NewClient(
+					transport.NewInProcessTransportWithOptions(s, transport.WithElicitationHandler(tc.handler)),
+					clientpkg.WithElicitationHandler(tc.handler),
+				)
+			}
+			defer func() {
+				err = mcpClient.Close()
+				require.NoError(t, err)
+			}()
+
+			require.NoError(t, mcpClient.Start(ctx))
+			initRequest := mcp.InitializeRequest{}
+			initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+			initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+			_, err = mcpClient.Initialize(ctx, initRequest)
+			require.NoError(t, err)
+
+			executeRequest := mcp.CallToolRequest{}
+			executeRequest.Params.Name = "execute"
+			executeRequest.Params.Arguments = map[string]any{
+				"tool":      "delete_network",
+				"arguments": map[string]any{"id": "abc"},
+			}
+			executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+			require.NoError(t, err)
+			assert.Equal(t, !tc.applied, executeResult.IsError)
+			assert.Contains(t, executeResult.Content[0].(mcp.TextContent).Text, tc.expected)
+
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditLogEndToEnd(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(path, 0)
+	require.NoError(t, err)
+	defer func() { _ = auditLog.Close() }()
+
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{}, nil).Once()
+	client.On("GetNetwork", mock.Anything, "default", "abc").Return(&unifi.Network{ID: "abc", Name: "Guest"}, nil).Once()
+	client.On("DeleteNetwork", mock.Anything, "default", "abc").Return(nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeLazy, AuditLog: auditLog})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	// Only the delete inside the batch is recorded.
+	batchRequest := mcp.CallToolRequest{}
+	batchRequest.Params.Name = "batch"
+	batchRequest.Params.Arguments = map[string]any{
+		"calls": []any{
+			map[string]any{"tool": "list_network"},
+			map[string]any{"tool": "delete_network", "arguments": map[string]any{"id": "abc"}},
+		},
+	}
+	_, err = mcpClient.CallTool(ctx, batchRequest)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var rec audit.Record
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	assert.Equal(t, "delete_network", rec.Tool)
+	assert.Equal(t, "default", rec.Site)
+	assert.Equal(t, audit.OutcomeSuccess, rec.Outcome)
+	assert.Equal(t, "Guest", rec.Before.(map[string]any)["name"])
+
+	client.AssertExpectations(t)
+}
+
+func TestUndoEndToEnd(t *testing.T) {
+	ctx := context.Background()
+
+	client := servermocks.NewClient(t)
+	client.On("GetNetwork", mock.Anything, "default", "abc").Return(&unifi.Network{ID: "abc", Name: "Guest"}, nil).Once()
+	client.On("DeleteNetwork", mock.Anything, "default", "abc").Return(nil).Once()
+	client.On("GetNetwork", mock.Anything, "default", "abc").Return(nil, errors.New("not found")).Once()
+	client.On("CreateNetwork", mock.Anything, "default", mock.MatchedBy(func(n *unifi.Network) bool {
+		return n.ID == "" && n.Name == "Guest"
+	})).Return(&unifi.Network{ID: "def", Name: "Guest"}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeLazy, UndoHistory: 10})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	toolsResult, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, toolsResult.Tools, 6)
+
+	executeRequest := mcp.CallToolRequest{}
+	executeRequest.Params.Name = "execute"
+	executeRequest.Params.Arguments = map[string]any{
+		"tool":      "delete_network",
+		"arguments": map[string]any{"id": "abc"},
+	}
+	executeResult, err := mcpClient.CallTool(ctx, executeRequest)
+	require.NoError(t, err)
+	require.False(t, executeResult.IsError)
+
+	// The deleted network is recreated from its snapshot.
+	undoRequest := mcp.CallToolRequest{}
+	undoRequest.Params.Name = "undo"
+	undoResult, err := mcpClient.CallTool(ctx, undoRequest)
+	require.NoError(t, err)
+	require.False(t, undoResult.IsError, undoResult.Content[0].(mcp.TextContent).Text)
+
+	listRequest := mcp.CallToolRequest{}
+	listRequest.Params.Name = "list_changes"
+	listResult, err := mcpClient.CallTool(ctx, listRequest)
+	require.NoError(t, err)
+
+	var changes []journal.Entry
+	require.NoError(t, json.Unmarshal([]byte(listResult.Content[0].(mcp.TextContent).Text), &changes))
+	require.Len(t, changes, 2)
+	assert.Equal(t, "create_network", changes[0].Tool)
+	assert.Equal(t, "def", changes[0].ResourceID)
+	assert.Equal(t, changes[1].ID, changes[0].UndoOf)
+	assert.Equal(t, "delete_network", changes[1].Tool)
+	assert.Equal(t, changes[0].ID, changes[1].UndoneBy)
+
+	// Nothing is left to undo.
+	undoResult, err = mcpClient.CallTool(ctx, undoRequest)
+	require.NoError(t, err)
+	assert.True(t, undoResult.IsError)
+	assert.Contains(t, undoResult.Content[0].(mcp.TextContent).Text, "no changes to undo")
+
+	client.AssertExpectations(t)
+}
+
+func TestMultiControllerEndToEnd(t *testing.T) {
+	ctx := context.Background()
+
+	home := servermocks.NewClient(t)
+	home.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{{ID: "h1"}}, nil).Twice()
+	home.On("BaseURL").Return("https://192.168.1.1").Once()
+	home.On("GetSystemInformation").Return(&unifi.SysInfo{Version: "9.0.114"}, nil).Once()
+
+	lab := servermocks.NewClient(t)
+	lab.On("ListNetwork", mock.Anything, "lab12345").Return([]unifi.Network{{ID: "l1"}}, nil).Twice()
+	lab.On("BaseURL").Return("https://10.0.0.1").Once()
+	lab.On("GetSystemInformation").Return(nil, errors.New("connection refused")).Once()
+	lab.On("GetNetwork", mock.Anything, "lab12345", "abc").Return(&unifi.Network{ID: "abc", Name: "Guest"}, nil).Once()
+	lab.On("DeleteNetwork", mock.Anything, "lab12345", "abc").Return(nil).Once()
+	lab.On("GetNetwork", mock.Anything, "lab12345", "abc").Return(nil, errors.New("not found")).Once()
+	lab.On("CreateNetwork", mock.Anything, "lab12345", mock.Anything).Return(&unifi.Network{ID: "def", Name: "Guest"}, nil).Once()
+
+	s, err := New(Options{
+		Client:      home,
+		Controller:  "home",
+		Controllers: []Controller{{Name: "lab", Client: lab, Site: "lab12345"}},
+		Mode:        ModeLazy,
+		UndoHistory: 10,
+	})
+	require.NoError(t, err)
+
+	mcpClient, err := clientpkg.NewInProcessClient(s)
+	require.NoError(t, err)
+	defer func() {
+		err = mcpClient.Close()
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, mcpClient.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1.0.0"}
+	_, err = mcpClient.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	toolsResult, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, toolsResult.Tools, 7)
+
+	callTool := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := mcpClient.CallTool(ctx, req)
+		require.NoError(t, err)
+		return result
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	// The catalog, descriptions and journal are global: those tools take no
+	// controller argument, and the tools they describe do.
+	for _, tool := range toolsResult.Tools {
+		switch tool.Name {
+		case "tool_index", "describe_tool", "list_changes", "undo", "list_controllers":
+			assert.NotContains(t, tool.InputSchema.Properties, "controller", tool.Name)
+		default:
+			assert.Contains(t, tool.InputSchema.Properties, "controller", tool.Name)
+		}
+	}
+	described := callTool("describe_tool", map[string]any{"tool": "list_network"})
+	require.False(t, described.IsError, text(described))
+	assert.Contains(t, text(described), `"controller"`)
+	assert.Contains(t, text(described), `"lab"`)
+
+	// Calls go to the default controller unless they name another one.
+	result := callTool("execute", map[string]any{"tool": "list_network"})
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "h1")
+
+	result = callTool("execute", map[string]any{"tool": "list_network", "controller": "lab"})
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "l1")
+
+	result = callTool("batch", map[string]any{
+		"controller": "lab",
+		"calls": []any{
+			map[string]any{"tool": "list_network"},
+			map[string]any{"tool": "list_network", "controller": "home"},
+		},
+	})
+	require.False(t, result.IsError, text(result))
+	var batchResults []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(text(result)), &batchResults))
+	require.Len(t, batchResults, 2)
+	assert.Equal(t, "l1", batchResults[0]["result"].([]any)[0].(map[string]any)["_id"])
+	assert.Equal(t, "h1", batchResults[1]["result"].([]any)[0].(map[string]any)["_id"])
+
+	result = callTool("execute", map[string]any{"tool": "list_network", "controller": "office"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), `unknown controller "office" (available: home, lab)`)
+
+	result = callTool("list_controllers", nil)
+	require.False(t, result.IsError, text(result))
+	var statuses []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(text(result)), &statuses))
+	require.Len(t, statuses, 2)
+	assert.Equal(t, map[string]any{"name": "home", "url": "https://192.168.1.1", "default": true, "reachable": true, "version": "9.0.114"}, statuses[0])
+	assert.Equal(t, map[string]any{"name": "lab", "url": "https://10.0.0.1", "default": false, "reachable": false, "error": "connection refused"}, statuses[1])
+
+	// Changes are undone on the controller they were made on.
+	result = callTool("execute", map[string]any{"tool": "delete_network", "controller": "lab", "arguments": map[string]any{"id": "abc"}})
+	require.False(t, result.IsError, text(result))
+	result = callTool("undo", nil)
+	require.False(t, result.IsError, text(result))
+
+	var changes []journal.Entry
+	require.NoError(t, json.Unmarshal([]byte(text(callTool("list_changes", nil))), &changes))
+	require.Len(t, changes, 2)
+	assert.Equal(t, "lab", changes[0].Controller)
+	assert.Equal(t, "create_network", changes[0].Tool)
+	assert.Equal(t, "lab", changes[1].Controller)
+
+	home.AssertExpectations(t)
+	lab.AssertExpectations(t)
+}
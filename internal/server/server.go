@@ -1,11 +1,30 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/claytono/go-unifi-mcp/internal/audit"
+	"github.com/claytono/go-unifi-mcp/internal/auth"
+	"github.com/claytono/go-unifi-mcp/internal/cache"
 	"github.com/claytono/go-unifi-mcp/internal/config"
+	"github.com/claytono/go-unifi-mcp/internal/confirm"
+	"github.com/claytono/go-unifi-mcp/internal/controllers"
+	"github.com/claytono/go-unifi-mcp/internal/journal"
 	"github.com/claytono/go-unifi-mcp/internal/meta"
+	"github.com/claytono/go-unifi-mcp/internal/ratelimit"
+	"github.com/claytono/go-unifi-mcp/internal/redact"
+	"github.com/claytono/go-unifi-mcp/internal/render"
+	"github.com/claytono/go-unifi-mcp/internal/sites"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
 	"github.com/claytono/go-unifi-mcp/internal/tools/registry"
 	"github.com/filipowm/go-unifi/unifi"
 	"github.com/mark3labs/mcp-go/server"
@@ -16,32 +35,77 @@ var Version = "dev"
 
 var newUnifiClient = unifi.NewClient
 
+var serveStdio = func(s *server.MCPServer) error {
+	return server.ServeStdio(s)
+}
+
+// shutdownTimeout bounds how long the HTTP transport waits for in-flight
+// requests to finish when the server is stopped.
+const shutdownTimeout = 10 * time.Second
+
+// HTTPEndpoint is the path the streamable HTTP transport is served on.
+const HTTPEndpoint = "/mcp"
+
 const ServerName = "go-unifi-mcp"
 
 // Mode determines how tools are registered with the MCP server.
 type Mode string
 
 const (
-	// ModeLazy registers only 3 meta-tools (~200 tokens context).
+	// ModeLazy registers only 4 meta-tools (~200 tokens context).
 	ModeLazy Mode = "lazy"
 	// ModeEager registers all 242 direct tools (~55K tokens context).
 	ModeEager Mode = "eager"
 )
 
+// Controller is an additional UniFi controller served alongside the default
+// one. Site and AllowedSites apply to its calls like the options of the same
+// name do to the default controller.
+type Controller struct {
+	Name         string
+	Client       unifi.Client
+	Site         string
+	AllowedSites []string
+}
+
 // Options configures server creation.
 type Options struct {
-	Client unifi.Client
-	Mode   Mode // defaults to ModeLazy if empty
+	Client        unifi.Client
+	Controller    string                   // name of Client when Controllers is set (defaults to "default")
+	Controllers   []Controller             // additional controllers, selected with the controller argument
+	Mode          Mode                     // defaults to ModeLazy if empty
+	Site          string                   // default site for calls without a site argument (defaults to "default")
+	AllowedSites  []string                 // if non-empty, calls for any other site are rejected
+	ReadOnly      bool                     // hide and refuse create, update and delete tools
+	DryRun        bool                     // preview create, update and delete calls instead of applying them
+	ConfirmTools  []string                 // mutating tool patterns that need user approval via elicitation (default: none)
+	RevealSecrets bool                     // show secret fields to callers without an identity, see auth.RevealSecrets
+	AuditLog      *audit.Logger            // if set, every create, update and delete call is recorded
+	UndoHistory   int                      // if positive, journal this many changes and register list_changes and undo
+	ToolTimeout   time.Duration            // if positive, deadline for each tool call, including each call of a batch
+	BatchWorkers  int                      // calls of a batch run at once (defaults to meta.DefaultBatchWorkers)
+	RateLimit     float64                  // if positive, tool calls per second allowed per controller
+	RateBurst     int                      // tool calls allowed in a burst before RateLimit applies
+	CacheTTL      time.Duration            // if positive, cache list and get results this long
+	CacheTTLs     map[string]time.Duration // per-resource cache TTLs overriding CacheTTL
+	OutputFormat  string                   // default format of tool results, see render.Formats (defaults to render.Default)
+	ToolsInclude  []string                 // tool patterns to expose (default: all), see registry.ToolFilter
+	ToolsExclude  []string                 // tool patterns to hide, see registry.ToolFilter
 }
 
 // New creates a new MCP server with UniFi tools registered.
-// In lazy mode (default), only 3 meta-tools are registered for reduced context.
+// In lazy mode (default), only 4 meta-tools are registered for reduced context.
 // In eager mode, all 242 direct tools are registered.
 func New(opts Options) (*server.MCPServer, error) {
 	if opts.Client == nil {
 		return nil, fmt.Errorf("client is required")
 	}
 
+	filter, err := registry.NewToolFilter(opts.ToolsInclude, opts.ToolsExclude)
+	if err != nil {
+		return nil, err
+	}
+
 	// Determine mode from options, environment, or default
 	mode := opts.Mode
 	if mode == "" {
@@ -55,22 +119,142 @@ func New(opts Options) (*server.MCPServer, error) {
 		ServerName,
 		Version,
 		server.WithToolCapabilities(true),
+		server.WithElicitation(),
 	)
 
+	confirmer, err := confirm.New(s, opts.ConfirmTools)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := controllerSet(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := render.Parse(opts.OutputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every tool call, direct or via execute/batch, is authorized against
+	// the caller's role, has its site resolved and, for high-risk tools, is
+	// approved by the user, then is audited and journaled as its handler
+	// runs.
+	// Results are rendered in the requested format last, so caching,
+	// auditing and journaling work on JSON. Secrets are masked just before,
+	// outside the cache, so cached results serve callers allowed to see
+	// them and callers that are not.
+	mws := []registry.Middleware{auth.ToolMiddleware, siteMiddleware(opts), render.Middleware(format), redact.Middleware(opts.RevealSecrets)}
+	// Cached reads skip the controller and everything after this point.
+	var readCache *cache.Cache
+	if opts.CacheTTL > 0 || len(opts.CacheTTLs) > 0 {
+		if readCache, err = cache.New(opts.CacheTTL, opts.CacheTTLs); err != nil {
+			return nil, err
+		}
+		mws = append(mws, readCache.Middleware)
+	}
+	mws = append(mws, confirmer.Middleware)
+	// The deadline starts once the user has approved the call, so waiting
+	// for approval does not count against it.
+	if opts.ToolTimeout > 0 {
+		mws = append(mws, registry.Deadline(opts.ToolTimeout))
+	}
+	// Waiting for the rate limit counts against the deadline. The limit and
+	// the deadline wrap auditing and journaling, so the snapshot they fetch
+	// is sent to the controller under both.
+	if opts.RateLimit > 0 {
+		mws = append(mws, ratelimit.New(opts.RateLimit, opts.RateBurst).Middleware)
+	}
+	if opts.AuditLog != nil {
+		mws = append(mws, opts.AuditLog.Middleware)
+	}
+	// Changes are journaled for undo if enabled, and for rolling back
+	// transactional batches either way.
+	var changes *journal.Journal
+	if opts.UndoHistory > 0 {
+		changes = journal.New(opts.UndoHistory)
+	}
+	mws = append(mws, changes.Middleware)
+	tools := registry.DefaultToolset().
+		Filter(filter).
+		Wrap(mws...)
+	if opts.DryRun {
+		tools = tools.Wrap(registry.ForceDryRun)
+	}
+	if readCache != nil {
+		tools = tools.WithArg(cache.Arg, cache.ArgSchema, readCache.Cached)
+	}
+	tools = tools.WithArg(render.Arg, render.ArgSchema, func(generated.ToolMetadata) bool { return true })
+	if opts.ReadOnly {
+		tools = tools.ReadOnly()
+	}
+	if set != nil {
+		tools = set.Toolset(tools)
+	}
+
 	if mode == ModeEager {
 		// Register all direct tools from metadata
-		if err := registry.RegisterAllTools(s, opts.Client); err != nil {
+		if err := registry.RegisterToolset(s, opts.Client, tools); err != nil {
 			return nil, fmt.Errorf("failed to register tools: %w", err)
 		}
 	} else {
-		// Register 3 meta-tools for lazy mode
-		meta.RegisterMetaTools(s, opts.Client)
+		// Register 4 meta-tools for lazy mode
+		meta.RegisterMetaToolset(s, opts.Client, tools, meta.Options{BatchWorkers: opts.BatchWorkers, Format: format})
+	}
+
+	// Nothing can change in read-only mode, so there is nothing to undo.
+	if changes != nil && !opts.ReadOnly {
+		meta.RegisterJournalTools(s, opts.Client, tools.Handlers, changes)
+	}
+	if set != nil {
+		set.Register(s)
 	}
 
 	return s, nil
 }
 
-// NewClient creates a UniFi client from configuration.
+// controllerSet returns the controllers to dispatch calls to, or nil if the
+// server has a single controller.
+func controllerSet(opts Options) (*controllers.Set, error) {
+	if len(opts.Controllers) == 0 {
+		return nil, nil
+	}
+	name := opts.Controller
+	if name == "" {
+		name = controllers.DefaultName
+	}
+	list := []controllers.Controller{{Name: name, Client: opts.Client}}
+	for _, c := range opts.Controllers {
+		list = append(list, controllers.Controller{Name: c.Name, Client: c.Client})
+	}
+	return controllers.NewSet(list)
+}
+
+// siteMiddleware resolves the site argument with the default site and
+// allowlist of the controller a call is sent to.
+func siteMiddleware(opts Options) registry.Middleware {
+	resolver := sites.NewResolver(opts.Site, opts.AllowedSites)
+	if len(opts.Controllers) == 0 {
+		return resolver.Middleware
+	}
+	byClient := map[unifi.Client]*sites.Resolver{opts.Client: resolver}
+	for _, c := range opts.Controllers {
+		byClient[c.Client] = sites.NewResolver(c.Site, c.AllowedSites)
+	}
+	return func(client unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		r, ok := byClient[client]
+		if !ok {
+			r = resolver
+		}
+		return r.Middleware(client, meta, next)
+	}
+}
+
+// NewClient creates a UniFi client from configuration. Idempotent requests
+// are retried as configured, see newTransport. If the controller's
+// certificate cannot be verified, the error describes the certificate it
+// presented.
 func NewClient(cfg *config.Config) (unifi.Client, error) {
 	clientCfg := &unifi.ClientConfig{
 		URL:       cfg.Host,
@@ -79,15 +263,133 @@ func NewClient(cfg *config.Config) (unifi.Client, error) {
 
 	if cfg.UseAPIKey() {
 		clientCfg.APIKey = cfg.APIKey
+		if cfg.APIKeySecret != nil {
+			// Runs after the client's own API key interceptor, so every
+			// request carries the key as last reloaded.
+			clientCfg.Interceptors = []unifi.ClientInterceptor{apiKeyInterceptor{cfg.APIKeySecret}}
+		}
 	} else {
 		clientCfg.User = cfg.Username
 		clientCfg.Password = cfg.Password
 	}
 
-	return newUnifiClient(clientCfg)
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	clientCfg.HttpRoundTripperProvider = func() http.RoundTripper { return transport }
+
+	client, err := newUnifiClient(clientCfg)
+	if err != nil {
+		return client, certificateError(err)
+	}
+	return client, nil
 }
 
-// Serve starts the MCP server on stdio.
-func Serve(s *server.MCPServer) error {
-	return server.ServeStdio(s)
+// apiKeyInterceptor sets the API key header from a secret that may be
+// reloaded while the server runs.
+type apiKeyInterceptor struct {
+	secret *config.Secret
+}
+
+func (i apiKeyInterceptor) InterceptRequest(req *http.Request) error {
+	req.Header.Set(unifi.ApiKeyHeader, i.secret.Value())
+	return nil
+}
+
+func (i apiKeyInterceptor) InterceptResponse(*http.Response) error {
+	return nil
+}
+
+// Serve starts the MCP server on the transport selected by the configuration.
+// The HTTP transport runs until SIGINT or SIGTERM is received and then shuts
+// down gracefully.
+func Serve(s *server.MCPServer, cfg *config.Config) error {
+	switch cfg.Transport {
+	case "", config.TransportStdio:
+		return serveStdio(s)
+	case config.TransportHTTP:
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return ServeHTTP(ctx, s, cfg)
+	default:
+		return fmt.Errorf("unknown transport %q", cfg.Transport)
+	}
+}
+
+// ServeHTTP serves the MCP server over streamable HTTP on cfg.HTTPAddr until
+// ctx is cancelled. When cfg.TokenFile is set, every request must carry a
+// bearer token from that file. Without one, the server only listens on a
+// loopback address, since anyone who can reach it can call every tool.
+func ServeHTTP(ctx context.Context, s *server.MCPServer, cfg *config.Config) error {
+	var tokens *auth.TokenStore
+	if cfg.TokenFile != "" {
+		var err error
+		tokens, err = auth.LoadTokenFile(cfg.TokenFile)
+		if err != nil {
+			return err
+		}
+	} else if exposed(cfg.HTTPAddr) {
+		return fmt.Errorf("refusing to serve HTTP on %s without authentication: set UNIFI_HTTP_TOKEN_FILE or listen on a loopback address such as %s",
+			cfg.HTTPAddr, config.DefaultHTTPAddr)
+	}
+
+	ln, err := net.Listen("tcp", cfg.HTTPAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.HTTPAddr, err)
+	}
+	return serveHTTP(ctx, s, ln, tokens)
+}
+
+// exposed reports whether a listen address is reachable from other hosts:
+// an empty or unspecified host, or any host other than localhost and
+// loopback IPs. Addresses that cannot be parsed are left to net.Listen to
+// reject.
+func exposed(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || !ip.IsLoopback()
+}
+
+// serveHTTP serves on an existing listener so tests can bind to a random port.
+// A nil token store disables authentication.
+func serveHTTP(ctx context.Context, s *server.MCPServer, ln net.Listener, tokens *auth.TokenStore) error {
+	httpServer := &http.Server{ReadHeaderTimeout: 10 * time.Second}
+	streamable := server.NewStreamableHTTPServer(s, server.WithStreamableHTTPServer(httpServer))
+
+	var handler http.Handler = streamable
+	if tokens != nil {
+		handler = auth.Middleware(tokens, handler)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(HTTPEndpoint, handler)
+	httpServer.Handler = mux
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := streamable.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
@@ -1,13 +1,23 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/claytono/go-unifi-mcp/internal/audit"
 	"github.com/claytono/go-unifi-mcp/internal/config"
 	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
 	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,7 +34,191 @@ func TestNew_CreatesServer(t *testing.T) {
 	s, err := New(Options{Client: client})
 	assert.NoError(t, err)
 	assert.NotNil(t, s)
-	assert.Len(t, s.ListTools(), 3)
+	assert.Len(t, s.ListTools(), 4)
+}
+
+func TestNew_InvalidToolFilter(t *testing.T) {
+	client := servermocks.NewClient(t)
+
+	_, err := New(Options{Client: client, ToolsExclude: []string{"owner:bob"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid exclude pattern")
+}
+
+func TestNew_ToolFilterEager(t *testing.T) {
+	client := servermocks.NewClient(t)
+
+	s, err := New(Options{
+		Client:       client,
+		Mode:         ModeEager,
+		ToolsInclude: []string{"resource:Network"},
+		ToolsExclude: []string{"category:delete"},
+	})
+	require.NoError(t, err)
+
+	tools := s.ListTools()
+	assert.Len(t, tools, 4)
+	assert.Contains(t, tools, "list_network")
+	assert.NotContains(t, tools, "delete_network")
+}
+
+func TestNew_UndoHistory(t *testing.T) {
+	client := servermocks.NewClient(t)
+
+	s, err := New(Options{Client: client, Mode: ModeLazy, UndoHistory: 10})
+	require.NoError(t, err)
+	tools := s.ListTools()
+	assert.Contains(t, tools, "list_changes")
+	assert.Contains(t, tools, "undo")
+
+	// Read-only servers make no changes to undo.
+	s, err = New(Options{Client: client, Mode: ModeLazy, UndoHistory: 10, ReadOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, s.ListTools(), 4)
+}
+
+func TestNew_ToolTimeout(t *testing.T) {
+	client := servermocks.NewClient(t)
+	var deadline time.Time
+	client.On("ListNetwork", mock.Anything, "default").Run(func(args mock.Arguments) {
+		deadline, _ = args.Get(0).(context.Context).Deadline()
+	}).Return([]unifi.Network{}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeEager, ToolTimeout: time.Minute})
+	require.NoError(t, err)
+	result, err := s.ListTools()["list_network"].Handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
+
+func TestNew_RateLimit(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{}, nil).Once()
+
+	s, err := New(Options{Client: client, Mode: ModeEager, RateLimit: 0.001, RateBurst: 1})
+	require.NoError(t, err)
+	handler := s.ListTools()["list_network"].Handler
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	// The bucket is empty, so the next call waits until its deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result, err = handler(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestNew_RateLimitCoversAuditAndJournal(t *testing.T) {
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), 0)
+	require.NoError(t, err)
+	defer func() { _ = auditLog.Close() }()
+
+	// An audited, journaled update reads the network once, for the audit
+	// log, the journal and the merge, and writes it once, all within the
+	// call's deadline.
+	client := servermocks.NewClient(t)
+	var calls int
+	inDeadline := func(args mock.Arguments) {
+		calls++
+		_, ok := args.Get(0).(context.Context).Deadline()
+		assert.True(t, ok, "controller call without a deadline")
+	}
+	client.On("GetNetwork", mock.Anything, "default", "n1").Run(inDeadline).
+		Return(&unifi.Network{ID: "n1", Name: "LAN"}, nil).Once()
+	client.On("UpdateNetwork", mock.Anything, "default", mock.Anything).Run(inDeadline).
+		Return(&unifi.Network{ID: "n1", Name: "Office"}, nil).Once()
+
+	s, err := New(Options{
+		Client: client, Mode: ModeEager, AuditLog: auditLog, UndoHistory: 10,
+		ToolTimeout: time.Minute, RateLimit: 0.001, RateBurst: 1,
+	})
+	require.NoError(t, err)
+	handler := s.ListTools()["update_network"].Handler
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"id": "n1", "name": "Office"}
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content)
+	assert.Equal(t, 2, calls)
+
+	// With the bucket empty, the next update waits for the rate limit
+	// before anything, the audit and journal snapshot included, reaches the
+	// controller.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result, err = handler(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 2, calls)
+}
+
+func TestNew_Cache(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{}, nil).Twice()
+
+	s, err := New(Options{Client: client, Mode: ModeEager, CacheTTL: time.Minute})
+	require.NoError(t, err)
+	tool := s.ListTools()["list_network"]
+	assert.Contains(t, string(tool.Tool.RawInputSchema), `"no_cache"`)
+	assert.NotContains(t, string(s.ListTools()["delete_network"].Tool.RawInputSchema), `"no_cache"`)
+
+	for _, args := range []map[string]any{nil, nil, {"no_cache": true}} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+	}
+
+	_, err = New(Options{Client: client, Mode: ModeEager, CacheTTLs: map[string]time.Duration{"Gadget": time.Second}})
+	assert.ErrorContains(t, err, "unknown resource: Gadget")
+}
+
+func TestNew_OutputFormat(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListNetwork", mock.Anything, "default").Return([]unifi.Network{{ID: "n1", Name: "LAN"}}, nil).Twice()
+
+	s, err := New(Options{Client: client, Mode: ModeEager, OutputFormat: "yaml"})
+	require.NoError(t, err)
+	tool := s.ListTools()["list_network"]
+	assert.Contains(t, string(tool.Tool.RawInputSchema), `"format"`)
+
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"fields": []any{"name"}}, "- name: LAN\n"},
+		{map[string]any{"fields": []any{"name"}, "format": "markdown"}, "| name |\n| --- |\n| LAN |\n"},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tc.args
+		result, err := tool.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, result.Content[0].(mcp.TextContent).Text)
+	}
+
+	_, err = New(Options{Client: client, Mode: ModeEager, OutputFormat: "xml"})
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+}
+
+func TestNew_RedactsSecrets(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("GetWLAN", mock.Anything, "default", "w1").Return(&unifi.WLAN{ID: "w1", Name: "Home", XPassphrase: "hunter22"}, nil)
+
+	get := func(opts Options) string {
+		s, err := New(opts)
+		require.NoError(t, err)
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"id": "w1", "fields": []any{"name", "x_passphrase"}, "format": "json"}
+		result, err := s.ListTools()["get_wlan"].Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	assert.Equal(t, `{"name":"Home","x_passphrase":"[REDACTED]"}`, get(Options{Client: client, Mode: ModeEager}))
+	assert.Equal(t, `{"name":"Home","x_passphrase":"hunter22"}`, get(Options{Client: client, Mode: ModeEager, RevealSecrets: true}))
 }
 
 func TestNewClient_APIKey(t *testing.T) {
@@ -56,6 +250,40 @@ func TestNewClient_APIKey(t *testing.T) {
 	assert.Empty(t, captured.Password)
 }
 
+func TestNewClient_ReloadableAPIKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("first-key"), 0o600))
+	cfg := &config.Config{Host: "https://192.168.1.1", APIKeyFile: keyFile}
+	require.NoError(t, cfg.ReadSecrets())
+
+	var captured *unifi.ClientConfig
+	prevFactory := newUnifiClient
+	newUnifiClient = func(clientCfg *unifi.ClientConfig) (unifi.Client, error) {
+		captured = clientCfg
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		newUnifiClient = prevFactory
+	})
+
+	_, err := NewClient(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, captured)
+	assert.Equal(t, "first-key", captured.APIKey)
+	require.Len(t, captured.Interceptors, 1)
+
+	apiKeyHeader := func() string {
+		req := httptest.NewRequest(http.MethodGet, "https://192.168.1.1/api/self", nil)
+		require.NoError(t, captured.Interceptors[0].InterceptRequest(req))
+		return req.Header.Get(unifi.ApiKeyHeader)
+	}
+	assert.Equal(t, "first-key", apiKeyHeader())
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("second-key"), 0o600))
+	require.NoError(t, cfg.ReloadSecrets())
+	assert.Equal(t, "second-key", apiKeyHeader())
+}
+
 func TestNewClient_UserPass(t *testing.T) {
 	cfg := &config.Config{
 		Host:      "https://192.168.1.1",
@@ -119,3 +347,95 @@ func TestMode_OptionsOverridesEnv(t *testing.T) {
 	opts := Options{Mode: ModeLazy}
 	assert.Equal(t, ModeLazy, opts.Mode)
 }
+
+func TestServe_Stdio(t *testing.T) {
+	expectedErr := errors.New("stdio")
+	var served *mcpserver.MCPServer
+	prevServe := serveStdio
+	serveStdio = func(s *mcpserver.MCPServer) error {
+		served = s
+		return expectedErr
+	}
+	t.Cleanup(func() {
+		serveStdio = prevServe
+	})
+
+	s := mcpserver.NewMCPServer("test", "1.0")
+	for _, transport := range []string{"", config.TransportStdio} {
+		served = nil
+		err := Serve(s, &config.Config{Transport: transport})
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Same(t, s, served)
+	}
+}
+
+func TestServe_UnknownTransport(t *testing.T) {
+	err := Serve(mcpserver.NewMCPServer("test", "1.0"), &config.Config{Transport: "smoke-signal"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown transport")
+}
+
+func TestServe_HTTPListenError(t *testing.T) {
+	err := Serve(mcpserver.NewMCPServer("test", "1.0"), &config.Config{
+		Transport: config.TransportHTTP,
+		HTTPAddr:  "invalid-address",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to listen")
+}
+
+func TestServeHTTP_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ServeHTTP(ctx, mcpserver.NewMCPServer("test", "1.0"), &config.Config{HTTPAddr: "127.0.0.1:0"})
+	assert.NoError(t, err)
+}
+
+func TestServeHTTP_InvalidTokenFile(t *testing.T) {
+	err := ServeHTTP(context.Background(), mcpserver.NewMCPServer("test", "1.0"), &config.Config{
+		HTTPAddr:  "127.0.0.1:0",
+		TokenFile: "/nonexistent/tokens.yaml",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token file")
+}
+
+func TestServeHTTP_RefusesExposedAddressWithoutTokens(t *testing.T) {
+	for _, addr := range []string{":0", "0.0.0.0:0", "[::]:0", "192.0.2.1:0", "example.com:0"} {
+		err := ServeHTTP(context.Background(), mcpserver.NewMCPServer("test", "1.0"), &config.Config{HTTPAddr: addr})
+		require.Error(t, err, addr)
+		assert.Contains(t, err.Error(), "without authentication", addr)
+	}
+}
+
+func TestExposed(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8080": false,
+		"[::1]:8080":     false,
+		"localhost:8080": false,
+		":8080":          true,
+		"0.0.0.0:8080":   true,
+		"10.0.0.5:8080":  true,
+		"mcp.lan:8080":   true,
+		"invalid":        false,
+	} {
+		assert.Equal(t, want, exposed(addr), addr)
+	}
+}
+
+func TestNew_Controllers(t *testing.T) {
+	home := servermocks.NewClient(t)
+	lab := servermocks.NewClient(t)
+
+	s, err := New(Options{Client: home, Mode: ModeEager, Controllers: []Controller{{Name: "lab", Client: lab}}})
+	require.NoError(t, err)
+	tools := s.ListTools()
+	assert.Len(t, tools, 243)
+	require.Contains(t, tools, "list_network")
+	assert.Contains(t, string(tools["list_network"].Tool.RawInputSchema), `"controller"`)
+
+	_, err = New(Options{Client: home, Controller: "lab", Controllers: []Controller{{Name: "lab", Client: lab}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `duplicate controller "lab"`)
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/config"
+)
+
+// errFingerprintMismatch is returned during the TLS handshake when the
+// controller's certificate does not match the configured fingerprint.
+var errFingerprintMismatch = errors.New("certificate does not match UNIFI_CERT_FINGERPRINT")
+
+// tlsConfig returns the TLS configuration for connecting to the controller,
+// or nil if neither a CA bundle nor a fingerprint is configured and the
+// client's default, governed by VerifySSL, applies.
+//
+// With a CA bundle, only certificates issued by those CAs are trusted and the
+// host name is checked as usual. With a fingerprint, the controller's
+// certificate must be exactly the pinned one; it may be self-signed and its
+// names are not checked. With both, both must hold. Either one enables
+// verification regardless of VerifySSL.
+func tlsConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.CACert == "" && cfg.CertFingerprint == "" {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", cfg.CACert)
+		}
+		tlsCfg.RootCAs = roots
+	}
+
+	if cfg.CertFingerprint != "" {
+		pin, err := config.ParseFingerprint(cfg.CertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		// The standard verification runs first when a CA bundle is set; the
+		// pin is checked on top of it. Without a bundle the pin replaces it.
+		tlsCfg.InsecureSkipVerify = cfg.CACert == ""
+		tlsCfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("controller presented no certificate")
+			}
+			if sum := sha256.Sum256(rawCerts[0]); !bytes.Equal(sum[:], pin) {
+				cert, err := x509.ParseCertificate(rawCerts[0])
+				if err != nil {
+					return fmt.Errorf("%w: controller presented an unparsable certificate with SHA-256 fingerprint %s",
+						errFingerprintMismatch, formatFingerprint(sum[:]))
+				}
+				return fmt.Errorf("%w: controller presented %s", errFingerprintMismatch, describeCertificate(cert))
+			}
+			return nil
+		}
+	}
+	return tlsCfg, nil
+}
+
+// certificateError adds a description of the certificate the controller
+// presented to a failed certificate verification, so the CA or fingerprint to
+// configure can be checked against it. Other errors are returned unchanged.
+func certificateError(err error) error {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) || len(verifyErr.UnverifiedCertificates) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w; controller presented %s", err, describeCertificate(verifyErr.UnverifiedCertificates[0]))
+}
+
+// describeCertificate summarizes a certificate for error messages.
+func describeCertificate(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	names := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	desc := fmt.Sprintf("certificate subject %q issued by %q, valid %s to %s",
+		cert.Subject.String(), cert.Issuer.String(),
+		cert.NotBefore.UTC().Format(time.RFC3339), cert.NotAfter.UTC().Format(time.RFC3339))
+	if len(names) > 0 {
+		desc += ", names " + strings.Join(names, ", ")
+	}
+	return desc + ", SHA-256 fingerprint " + formatFingerprint(sum[:])
+}
+
+// formatFingerprint formats a fingerprint the way openssl prints it, which
+// config.ParseFingerprint accepts.
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSServer starts an HTTPS server with a new self-signed certificate for
+// 127.0.0.1 and returns it with the certificate's fingerprint.
+func newTLSServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "unifi", Organization: []string{"Ubiquiti Inc."}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	sum := sha256.Sum256(der)
+	return ts, formatFingerprint(sum[:])
+}
+
+// get requests the server's root with the TLS configuration built for cfg,
+// wrapping failures like NewClient does.
+func get(t *testing.T, ts *httptest.Server, cfg *config.Config) error {
+	t.Helper()
+	tlsCfg, err := tlsConfig(cfg)
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		return certificateError(err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+func TestTLSConfig_DefaultIsNil(t *testing.T) {
+	tlsCfg, err := tlsConfig(&config.Config{VerifySSL: true})
+	require.NoError(t, err)
+	assert.Nil(t, tlsCfg)
+}
+
+func TestTLSConfig_CABundle(t *testing.T) {
+	ts, _ := newTLSServer(t)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+
+	assert.NoError(t, get(t, ts, &config.Config{CACert: caFile}))
+
+	// Another server's certificate is not trusted.
+	other, fingerprint := newTLSServer(t)
+	err := get(t, other, &config.Config{CACert: caFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "controller presented certificate")
+	assert.Contains(t, err.Error(), "SHA-256 fingerprint "+fingerprint)
+}
+
+func TestTLSConfig_CABundleErrors(t *testing.T) {
+	_, err := tlsConfig(&config.Config{CACert: filepath.Join(t.TempDir(), "missing.pem")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read CA bundle")
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0o600))
+	_, err = tlsConfig(&config.Config{CACert: empty})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains no PEM certificates")
+}
+
+func TestTLSConfig_Fingerprint(t *testing.T) {
+	ts, fingerprint := newTLSServer(t)
+
+	// Self-signed certificates are accepted when pinned, whatever VerifySSL
+	// says.
+	assert.NoError(t, get(t, ts, &config.Config{CertFingerprint: fingerprint, VerifySSL: true}))
+	assert.NoError(t, get(t, ts, &config.Config{CertFingerprint: fingerprint, VerifySSL: false}))
+
+	other, otherFingerprint := newTLSServer(t)
+	err := get(t, other, &config.Config{CertFingerprint: fingerprint})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errFingerprintMismatch)
+	assert.Contains(t, err.Error(), "SHA-256 fingerprint "+otherFingerprint)
+	assert.Contains(t, err.Error(), `issued by "CN=unifi,O=Ubiquiti Inc."`)
+}
+
+func TestTLSConfig_CABundleAndFingerprint(t *testing.T) {
+	ts, fingerprint := newTLSServer(t)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+
+	assert.NoError(t, get(t, ts, &config.Config{CACert: caFile, CertFingerprint: fingerprint}))
+
+	_, otherFingerprint := newTLSServer(t)
+	err := get(t, ts, &config.Config{CACert: caFile, CertFingerprint: otherFingerprint})
+	assert.ErrorIs(t, err, errFingerprintMismatch)
+}
+
+func TestNewClient_DescribesPresentedCertificate(t *testing.T) {
+	ts, fingerprint := newTLSServer(t)
+
+	_, err := NewClient(&config.Config{Host: ts.URL, APIKey: "test-key", VerifySSL: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SHA-256 fingerprint "+fingerprint)
+}
+
+func TestDescribeCertificate_LeavesCertificateAlone(t *testing.T) {
+	dnsNames := make([]string, 1, 4)
+	dnsNames[0] = "unifi.lan"
+	cert := &x509.Certificate{DNSNames: dnsNames, IPAddresses: []net.IP{net.ParseIP("192.168.1.1")}}
+
+	assert.Contains(t, describeCertificate(cert), "names unifi.lan, 192.168.1.1")
+	assert.Equal(t, []string{"unifi.lan"}, cert.DNSNames)
+	assert.Equal(t, "", dnsNames[:2][1], "the spare capacity is not written to")
+}
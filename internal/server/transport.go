@@ -0,0 +1,143 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/config"
+)
+
+// maxRetryDelay caps the delay between retries, including delays requested
+// by the controller with Retry-After.
+const maxRetryDelay = 30 * time.Second
+
+// newTransport returns the round tripper used to talk to the controller: an
+// HTTP transport with the configured TLS settings, proxy and timeouts,
+// wrapped to retry idempotent requests.
+func newTransport(cfg *config.Config) (http.RoundTripper, error) {
+	tlsCfg, err := tlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg == nil {
+		tlsCfg = &tls.Config{InsecureSkipVerify: !cfg.VerifySSL} //nolint:gosec // opt-in via UNIFI_VERIFY_SSL=false
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.Timeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsCfg,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+	if cfg.Retries == 0 {
+		return transport, nil
+	}
+	return &retryTransport{next: transport, retries: cfg.Retries, backoff: cfg.RetryBackoff}, nil
+}
+
+// retryTransport retries idempotent requests that fail with a 5xx or 429
+// status or a reset connection, waiting with exponential backoff and jitter
+// between attempts. Other requests are sent once, since the controller may
+// have applied them even if the response was lost.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt == t.retries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.delay(attempt, resp)
+		if resp != nil {
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns how long to wait before retrying after the given attempt:
+// the backoff doubled for each attempt, with half of it randomized so
+// clients do not retry in lockstep. A Retry-After header, if present, is
+// honored instead.
+func (t *retryTransport) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, maxRetryDelay)
+		}
+	}
+	d := min(t.backoff<<attempt, maxRetryDelay)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// isIdempotent reports whether a request can be sent again without changing
+// its effect. Requests with a body that cannot be replayed are not.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// shouldRetry reports whether an attempt failed in a way worth retrying.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
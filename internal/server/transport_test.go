@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer starts a server that fails the first failures requests
+// with fail and then answers 200 with the request body, counting requests.
+func newFlakyServer(t *testing.T, failures int32, fail func(http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var count atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) <= failures {
+			fail(w)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &count
+}
+
+func status(code int) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) { w.WriteHeader(code) }
+}
+
+func testTransport(t *testing.T, retries int) http.RoundTripper {
+	t.Helper()
+	transport, err := newTransport(&config.Config{
+		VerifySSL:    true,
+		Timeout:      time.Second,
+		Retries:      retries,
+		RetryBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	return transport
+}
+
+func do(t *testing.T, transport http.RoundTripper, method, url, body string) (*http.Response, error) {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, r)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err == nil {
+		t.Cleanup(func() { _ = resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func TestRetryTransport_RetriesIdempotentRequests(t *testing.T) {
+	for _, code := range []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusTooManyRequests} {
+		ts, count := newFlakyServer(t, 2, status(code))
+
+		resp, err := do(t, testTransport(t, 2), http.MethodPut, ts.URL, `{"name":"lan"}`)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, code)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"name":"lan"}`, string(body), "body is sent again on retry")
+		assert.Equal(t, int32(3), count.Load())
+	}
+}
+
+func TestRetryTransport_GivesUp(t *testing.T) {
+	ts, count := newFlakyServer(t, 10, status(http.StatusInternalServerError))
+
+	resp, err := do(t, testTransport(t, 2), http.MethodGet, ts.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestRetryTransport_DoesNotRetry(t *testing.T) {
+	// Creates are not idempotent.
+	ts, count := newFlakyServer(t, 1, status(http.StatusServiceUnavailable))
+	resp, err := do(t, testTransport(t, 2), http.MethodPost, ts.URL, `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), count.Load())
+
+	// Client errors will not go away.
+	ts, count = newFlakyServer(t, 1, status(http.StatusNotFound))
+	resp, err = do(t, testTransport(t, 2), http.MethodGet, ts.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, int32(1), count.Load())
+
+	// Retries are disabled.
+	ts, count = newFlakyServer(t, 1, status(http.StatusServiceUnavailable))
+	resp, err = do(t, testTransport(t, 0), http.MethodGet, ts.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), count.Load())
+}
+
+func TestRetryTransport_ConnectionReset(t *testing.T) {
+	hangUp := func(w http.ResponseWriter) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}
+	ts, count := newFlakyServer(t, 2, hangUp)
+
+	resp, err := do(t, testTransport(t, 2), http.MethodGet, ts.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	transport := &retryTransport{backoff: time.Hour}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	assert.Equal(t, 2*time.Second, transport.delay(0, resp))
+
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, maxRetryDelay, transport.delay(0, resp))
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	transport := &retryTransport{backoff: 100 * time.Millisecond}
+	for attempt, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		d := transport.delay(attempt, nil)
+		assert.GreaterOrEqual(t, d, base/2)
+		assert.LessOrEqual(t, d, base)
+	}
+	assert.LessOrEqual(t, transport.delay(20, nil), maxRetryDelay)
+}
+
+func TestRetryTransport_StopsWhenCanceled(t *testing.T) {
+	ts, count := newFlakyServer(t, 10, status(http.StatusServiceUnavailable))
+	transport := &retryTransport{next: http.DefaultTransport, retries: 5, backoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), count.Load())
+}
+
+func TestNewTransport_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	transport, err := newTransport(&config.Config{Timeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = do(t, transport, http.MethodGet, ts.URL, "")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	var proxied atomic.Bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.Host == "unifi.example.com")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(proxy.Close)
+
+	transport, err := newTransport(&config.Config{Proxy: proxy.URL, Timeout: time.Second})
+	require.NoError(t, err)
+	resp, err := do(t, transport, http.MethodGet, "http://unifi.example.com/api/self", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, proxied.Load())
+}
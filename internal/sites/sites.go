@@ -0,0 +1,202 @@
+// Package sites resolves the site argument of tool calls. It applies the
+// configured default site, translates site descriptions (as shown in the
+// UniFi UI) to the controller's internal short names, and enforces an
+// optional site allowlist.
+package sites
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultSite is the short name of the site every controller starts with.
+const DefaultSite = "default"
+
+// shortNamePattern matches the internal site names the controller generates.
+// Values that match are used as-is without asking the controller.
+var shortNamePattern = regexp.MustCompile(`^(default|[a-z0-9]{8})$`)
+
+// Resolver fills in and validates the site argument of tool calls.
+type Resolver struct {
+	defaultSite string
+	allowed     []string
+
+	mu    sync.Mutex
+	sites map[unifi.Client]*siteIndex
+}
+
+// siteIndex caches the sites of one controller.
+type siteIndex struct {
+	byName        map[string]unifi.Site
+	byDescription map[string]unifi.Site // keyed by lowercased description
+	allowed       map[string]struct{}   // resolved short names, nil until needed
+}
+
+// NewResolver returns a resolver that uses defaultSite when a call has no site
+// argument. If allowed is non-empty, calls for any other site are rejected.
+// Both may be given as short names or descriptions.
+func NewResolver(defaultSite string, allowed []string) *Resolver {
+	if defaultSite == "" {
+		defaultSite = DefaultSite
+	}
+	return &Resolver{
+		defaultSite: defaultSite,
+		allowed:     allowed,
+		sites:       make(map[unifi.Client]*siteIndex),
+	}
+}
+
+// Middleware rewrites the site argument to the resolved short name before the
+// handler runs, and refuses calls for sites outside the allowlist.
+func (r *Resolver) Middleware(client unifi.Client, _ generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		site, _ := args["site"].(string)
+
+		resolved, err := r.Resolve(ctx, client, site)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		newArgs := make(map[string]any, len(args)+1)
+		for k, v := range args {
+			newArgs[k] = v
+		}
+		newArgs["site"] = resolved
+		req.Params.Arguments = newArgs
+
+		return next(ctx, req)
+	}
+}
+
+// Resolve returns the short name for site, falling back to the default site
+// when site is empty.
+func (r *Resolver) Resolve(ctx context.Context, client unifi.Client, site string) (string, error) {
+	if site == "" {
+		site = r.defaultSite
+	}
+
+	name, err := r.lookup(ctx, client, site)
+	if err != nil {
+		return "", err
+	}
+
+	if len(r.allowed) > 0 {
+		allowed, err := r.allowedNames(ctx, client)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := allowed[name]; !ok {
+			return "", fmt.Errorf("site %q is not allowed (allowed sites: %s)", site, strings.Join(r.allowed, ", "))
+		}
+	}
+
+	return name, nil
+}
+
+// lookup translates a short name or description to a short name. Values that
+// look like short names are returned without contacting the controller.
+func (r *Resolver) lookup(ctx context.Context, client unifi.Client, site string) (string, error) {
+	if shortNamePattern.MatchString(site) {
+		return site, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.sites[client].find(site); ok {
+		return s.Name, nil
+	}
+
+	// Unknown value: the site list may have changed, so refresh it.
+	index, err := r.refresh(ctx, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve site %q: %w", site, err)
+	}
+	if s, ok := index.find(site); ok {
+		return s.Name, nil
+	}
+	return "", fmt.Errorf("unknown site %q (known sites: %s)", site, index.describe())
+}
+
+// allowedNames resolves the allowlist to short names once per controller.
+func (r *Resolver) allowedNames(ctx context.Context, client unifi.Client) (map[string]struct{}, error) {
+	r.mu.Lock()
+	if index := r.sites[client]; index != nil && index.allowed != nil {
+		r.mu.Unlock()
+		return index.allowed, nil
+	}
+	r.mu.Unlock()
+
+	allowed := make(map[string]struct{}, len(r.allowed))
+	for _, site := range r.allowed {
+		name, err := r.lookup(ctx, client, site)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed site: %w", err)
+		}
+		allowed[name] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.sites[client]
+	if index == nil {
+		index = &siteIndex{}
+		r.sites[client] = index
+	}
+	index.allowed = allowed
+	return allowed, nil
+}
+
+// refresh reloads the site list from the controller. Callers must hold r.mu.
+func (r *Resolver) refresh(ctx context.Context, client unifi.Client) (*siteIndex, error) {
+	list, err := client.ListSites(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &siteIndex{
+		byName:        make(map[string]unifi.Site, len(list)),
+		byDescription: make(map[string]unifi.Site, len(list)),
+	}
+	if prev := r.sites[client]; prev != nil {
+		index.allowed = prev.allowed
+	}
+	for _, s := range list {
+		index.byName[s.Name] = s
+		if s.Description != "" {
+			index.byDescription[strings.ToLower(s.Description)] = s
+		}
+	}
+	r.sites[client] = index
+	return index, nil
+}
+
+func (i *siteIndex) find(site string) (unifi.Site, bool) {
+	if i == nil || i.byName == nil {
+		return unifi.Site{}, false
+	}
+	if s, ok := i.byName[site]; ok {
+		return s, true
+	}
+	s, ok := i.byDescription[strings.ToLower(site)]
+	return s, ok
+}
+
+func (i *siteIndex) describe() string {
+	known := make([]string, 0, len(i.byName))
+	for _, s := range i.byName {
+		known = append(known, fmt.Sprintf("%s (%s)", s.Name, s.Description))
+	}
+	sort.Strings(known)
+	return strings.Join(known, ", ")
+}
@@ -0,0 +1,136 @@
+package sites
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	servermocks "github.com/claytono/go-unifi-mcp/internal/server/mocks"
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testSites = []unifi.Site{
+	{Name: "default", Description: "Default"},
+	{Name: "k2m4x9qa", Description: "Branch Office"},
+	{Name: "p8w3n1zd", Description: "Lab"},
+}
+
+func TestResolve_DefaultSite(t *testing.T) {
+	client := servermocks.NewClient(t)
+
+	site, err := NewResolver("", nil).Resolve(context.Background(), client, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSite, site)
+
+	site, err = NewResolver("k2m4x9qa", nil).Resolve(context.Background(), client, "")
+	require.NoError(t, err)
+	assert.Equal(t, "k2m4x9qa", site)
+}
+
+func TestResolve_ShortNamesSkipLookup(t *testing.T) {
+	// The mock fails the test if ListSites is called.
+	client := servermocks.NewClient(t)
+
+	site, err := NewResolver("", nil).Resolve(context.Background(), client, "p8w3n1zd")
+	require.NoError(t, err)
+	assert.Equal(t, "p8w3n1zd", site)
+}
+
+func TestResolve_Description(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return(testSites, nil).Once()
+
+	r := NewResolver("Branch Office", nil)
+
+	site, err := r.Resolve(context.Background(), client, "")
+	require.NoError(t, err)
+	assert.Equal(t, "k2m4x9qa", site)
+
+	// Case-insensitive and cached: no second ListSites call.
+	site, err = r.Resolve(context.Background(), client, "branch office")
+	require.NoError(t, err)
+	assert.Equal(t, "k2m4x9qa", site)
+}
+
+func TestResolve_UnknownSite(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return(testSites, nil)
+
+	_, err := NewResolver("", nil).Resolve(context.Background(), client, "Head Office")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown site "Head Office"`)
+	assert.Contains(t, err.Error(), "k2m4x9qa (Branch Office)")
+}
+
+func TestResolve_ListSitesError(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return(nil, errors.New("forbidden"))
+
+	_, err := NewResolver("", nil).Resolve(context.Background(), client, "Branch Office")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve site")
+	assert.Contains(t, err.Error(), "forbidden")
+}
+
+func TestResolve_Allowlist(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return(testSites, nil).Once()
+
+	r := NewResolver("", []string{"default", "Branch Office"})
+
+	for _, site := range []string{"", "default", "k2m4x9qa", "Branch Office"} {
+		resolved, err := r.Resolve(context.Background(), client, site)
+		require.NoError(t, err, site)
+		assert.NotEmpty(t, resolved)
+	}
+
+	_, err := r.Resolve(context.Background(), client, "p8w3n1zd")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `site "p8w3n1zd" is not allowed`)
+	assert.Contains(t, err.Error(), "default, Branch Office")
+}
+
+func TestResolve_InvalidAllowlistEntry(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return(testSites, nil)
+
+	_, err := NewResolver("", []string{"Nowhere"}).Resolve(context.Background(), client, "default")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid allowed site")
+}
+
+func TestMiddleware(t *testing.T) {
+	client := servermocks.NewClient(t)
+	client.On("ListSites", mock.Anything).Return(testSites, nil).Once()
+
+	r := NewResolver("Lab", []string{"Lab", "Branch Office"})
+	var seen map[string]any
+	handler := r.Middleware(client, generated.ToolMetadata{Name: "list_network"},
+		func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			seen = req.GetArguments()
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+	// Missing site uses the resolved default; other arguments are preserved.
+	args := map[string]any{"id": "abc"}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, map[string]any{"id": "abc", "site": "p8w3n1zd"}, seen)
+	assert.NotContains(t, args, "site", "caller's arguments must not be modified")
+
+	// Disallowed sites never reach the handler.
+	seen = nil
+	req.Params.Arguments = map[string]any{"site": "default"}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Nil(t, seen)
+}
@@ -0,0 +1,98 @@
+package generated
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DryRunArg is the argument that asks a mutating tool to preview its change
+// instead of sending it to the controller.
+const DryRunArg = "dry_run"
+
+// DryRunPlan describes the change a mutating tool would have made.
+type DryRunPlan struct {
+	DryRun   bool          `json:"dry_run"`
+	Action   string        `json:"action"` // create, update or delete
+	Resource string        `json:"resource"`
+	Site     string        `json:"site"`
+	ID       string        `json:"id,omitempty"`
+	Object   any           `json:"object,omitempty"`  // object that would be sent, or removed for deletes
+	Changes  []FieldChange `json:"changes,omitempty"` // updates only
+}
+
+// FieldChange is a single field that an update would modify. Nested objects
+// are compared field by field and reported with dotted paths.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// isDryRun reports whether the call asked for a dry run.
+func isDryRun(args map[string]any) bool {
+	dryRun, _ := args[DryRunArg].(bool)
+	return dryRun
+}
+
+// dryRunResult renders a plan as the tool result.
+func dryRunResult(plan DryRunPlan) (*mcp.CallToolResult, error) {
+	plan.DryRun = true
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal dry run plan: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// toJSONMap round-trips a value through JSON so it can be compared with other
+// decoded objects.
+func toJSONMap(v any) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DiffFields returns the fields that differ between two decoded objects, sorted
+// by path.
+func DiffFields(before, after map[string]any) []FieldChange {
+	changes := appendFieldChanges(nil, "", before, after)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func appendFieldChanges(changes []FieldChange, prefix string, before, after map[string]any) []FieldChange {
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+
+	for k := range keys {
+		path := prefix + k
+		b, a := before[k], after[k]
+		bMap, bIsMap := b.(map[string]any)
+		aMap, aIsMap := a.(map[string]any)
+		if bIsMap && aIsMap {
+			changes = appendFieldChanges(changes, path+".", bMap, aMap)
+			continue
+		}
+		if !reflect.DeepEqual(b, a) {
+			changes = append(changes, FieldChange{Field: path, Before: b, After: a})
+		}
+	}
+	return changes
+}
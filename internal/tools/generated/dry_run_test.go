@@ -0,0 +1,151 @@
+package generated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dryRunClient fails every mutating call so tests can prove dry runs never
+// reach the controller.
+type dryRunClient struct {
+	mergeUpdateClient
+}
+
+func (c *dryRunClient) CreateTest(_ context.Context, _ string, _ any) (any, error) {
+	return nil, errors.New("create must not be called")
+}
+
+func (c *dryRunClient) UpdateTest(_ context.Context, _ string, _ any) (any, error) {
+	return nil, errors.New("update must not be called")
+}
+
+func (c *dryRunClient) DeleteTest(_ context.Context, _, _ string) error {
+	return errors.New("delete must not be called")
+}
+
+func decodePlan(t *testing.T, result *mcp.CallToolResult) DryRunPlan {
+	t.Helper()
+	require.NotNil(t, result)
+	require.False(t, result.IsError, result.Content)
+	var plan DryRunPlan
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &plan))
+	assert.True(t, plan.DryRun)
+	return plan
+}
+
+func TestGenericCreate_DryRun(t *testing.T) {
+	handler := GenericCreate(&dryRunClient{}, "Test", func() any { return &mergeTestResource{} })
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"site":    "default",
+		"name":    "new item",
+		"dry_run": true,
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+
+	plan := decodePlan(t, result)
+	assert.Equal(t, "create", plan.Action)
+	assert.Equal(t, "Test", plan.Resource)
+	assert.Equal(t, "default", plan.Site)
+	assert.Equal(t, map[string]any{"_id": "", "name": "new item", "enabled": false}, plan.Object)
+	assert.Empty(t, plan.Changes)
+}
+
+func TestGenericCreate_DryRunFalseCallsClient(t *testing.T) {
+	handler := GenericCreate(&dryRunClient{}, "Test", func() any { return &mergeTestResource{} })
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"site":    "default",
+		"name":    "new item",
+		"dry_run": false,
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "create must not be called")
+}
+
+func TestGenericUpdate_DryRunReportsChanges(t *testing.T) {
+	client := &dryRunClient{}
+	handler := GenericUpdate(client, "Test", func() any { return &mergeTestResource{} }, false)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"site":    "default",
+		"id":      "123",
+		"name":    "renamed",
+		"enabled": true,
+		"dry_run": true,
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+
+	plan := decodePlan(t, result)
+	assert.Equal(t, "update", plan.Action)
+	assert.Equal(t, "123", plan.ID)
+	assert.Equal(t, map[string]any{"_id": "123", "name": "renamed", "enabled": true}, plan.Object)
+	// enabled is unchanged, so only the name shows up.
+	assert.Equal(t, []FieldChange{{Field: "name", Before: "existing", After: "renamed"}}, plan.Changes)
+	assert.Nil(t, client.updated)
+}
+
+func TestGenericDelete_DryRunShowsObject(t *testing.T) {
+	handler := GenericDelete(&dryRunClient{}, "Test")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"site": "default", "id": "123", "dry_run": true}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+
+	plan := decodePlan(t, result)
+	assert.Equal(t, "delete", plan.Action)
+	assert.Equal(t, "123", plan.ID)
+	assert.Equal(t, map[string]any{"_id": "123", "name": "existing", "enabled": true}, plan.Object)
+}
+
+func TestGenericDelete_DryRunGetError(t *testing.T) {
+	handler := GenericDelete(&FakeTestClient{ShouldError: true}, "Test")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"site": "default", "id": "123", "dry_run": true}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "get error")
+}
+
+func TestDiffFields(t *testing.T) {
+	before := map[string]any{
+		"name":    "lan",
+		"vlan":    float64(10),
+		"removed": "x",
+		"dhcp":    map[string]any{"start": "10.0.0.10", "stop": "10.0.0.99"},
+	}
+	after := map[string]any{
+		"name":  "lan",
+		"vlan":  float64(20),
+		"added": true,
+		"dhcp":  map[string]any{"start": "10.0.0.10", "stop": "10.0.0.200"},
+	}
+
+	assert.Equal(t, []FieldChange{
+		{Field: "added", Before: nil, After: true},
+		{Field: "dhcp.stop", Before: "10.0.0.99", After: "10.0.0.200"},
+		{Field: "removed", Before: "x", After: nil},
+		{Field: "vlan", Before: float64(10), After: float64(20)},
+	}, DiffFields(before, after))
+}
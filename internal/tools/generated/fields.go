@@ -0,0 +1,153 @@
+package generated
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FieldsArg is the argument that limits the output of list and get tools to
+// some fields of each object.
+const FieldsArg = "fields"
+
+// fieldSegment matches one step of a field path: a field name, optionally
+// marked with [] as an array whose elements are projected.
+var fieldSegment = regexp.MustCompile(`^[A-Za-z0-9_-]+(\[\])?$`)
+
+// fieldTree holds the requested paths by field name. A nil subtree selects
+// the whole value of the field.
+type fieldTree map[string]fieldTree
+
+// fieldsArg parses the fields argument into a tree of paths. It returns nil
+// if the argument is absent or empty, selecting everything.
+func fieldsArg(args map[string]any) (fieldTree, error) {
+	raw, ok := args[FieldsArg]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	var paths []string
+	switch v := raw.(type) {
+	case []string:
+		paths = v
+	case []any:
+		for _, item := range v {
+			path, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be an array of strings", FieldsArg)
+			}
+			paths = append(paths, path)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be an array of strings", FieldsArg)
+	}
+
+	var tree fieldTree
+	for _, path := range paths {
+		segments := strings.Split(strings.TrimSpace(path), ".")
+		for _, seg := range segments {
+			if !fieldSegment.MatchString(seg) {
+				return nil, fmt.Errorf("invalid field path %q: use names separated by dots, such as port_table[].port_idx", path)
+			}
+		}
+		if tree == nil {
+			tree = make(fieldTree)
+		}
+		tree.add(segments)
+	}
+	return tree, nil
+}
+
+// add adds a path to the tree. A path that selects a whole field replaces
+// any narrower paths below it.
+func (t fieldTree) add(segments []string) {
+	name := strings.TrimSuffix(segments[0], "[]")
+	sub, seen := t[name]
+	if len(segments) == 1 {
+		t[name] = nil
+		return
+	}
+	if seen && sub == nil {
+		return
+	}
+	if sub == nil {
+		sub = make(fieldTree)
+		t[name] = sub
+	}
+	sub.add(segments[1:])
+}
+
+// project returns the parts of a decoded JSON value selected by the tree.
+// Arrays are projected element by element. Fields missing from an object are
+// left out, and ok is false for a scalar that has no such fields.
+func (t fieldTree) project(v any) (out any, ok bool) {
+	if t == nil {
+		return v, true
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(t))
+		for name, sub := range t {
+			value, present := v[name]
+			if !present {
+				continue
+			}
+			if projected, ok := sub.project(value); ok {
+				obj[name] = projected
+			}
+		}
+		return obj, true
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			if projected, ok := t.project(item); ok {
+				items = append(items, projected)
+			}
+		}
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+// readResult renders the result of a list or get call, keeping only the
+// elements that match the filter and the requested fields of each.
+func readResult(v any, filter filterExpr, fields fieldTree) (*mcp.CallToolResult, error) {
+	if filter != nil || fields != nil {
+		decoded, err := decodeJSON(v)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %v", err)), nil
+		}
+		v = applyFilter(decoded, filter)
+	}
+	return renderJSON(v, fields)
+}
+
+// renderJSON renders a value as the tool result, keeping only the requested
+// fields of a decoded value.
+func renderJSON(v any, fields fieldTree) (*mcp.CallToolResult, error) {
+	if projected, ok := fields.project(v); ok {
+		v = projected
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// decodeJSON round-trips a value through JSON into maps and slices, so paths
+// can be looked up in it.
+func decodeJSON(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
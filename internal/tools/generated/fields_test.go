@@ -0,0 +1,90 @@
+package generated
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldsTestPort struct {
+	PortIdx int    `json:"port_idx"`
+	Name    string `json:"name"`
+}
+
+type fieldsTestDevice struct {
+	ID        string           `json:"_id"`
+	Name      string           `json:"name"`
+	MAC       string           `json:"mac"`
+	PortTable []fieldsTestPort `json:"port_table,omitempty"`
+	Config    map[string]any   `json:"config,omitempty"`
+}
+
+type fieldsTestClient struct{}
+
+func (c *fieldsTestClient) ListTest(_ context.Context, _ string) ([]fieldsTestDevice, error) {
+	return []fieldsTestDevice{
+		{ID: "1", Name: "switch", MAC: "aa", PortTable: []fieldsTestPort{{PortIdx: 1, Name: "uplink"}, {PortIdx: 2, Name: "ap"}}},
+		{ID: "2", Name: "gateway", MAC: "bb", Config: map[string]any{"mode": "router", "vlan": 10}},
+	}, nil
+}
+
+func (c *fieldsTestClient) GetTest(_ context.Context, _, id string) (*fieldsTestDevice, error) {
+	return &fieldsTestDevice{ID: id, Name: "gateway", MAC: "bb", Config: map[string]any{"mode": "router", "vlan": 10}}, nil
+}
+
+func TestFieldsArg(t *testing.T) {
+	tree, err := fieldsArg(map[string]any{})
+	require.NoError(t, err)
+	assert.Nil(t, tree)
+
+	tree, err = fieldsArg(map[string]any{FieldsArg: []any{"name", "port_table[].port_idx", "config.mode", "config"}})
+	require.NoError(t, err)
+	assert.Equal(t, fieldTree{
+		"name":       nil,
+		"port_table": fieldTree{"port_idx": nil},
+		"config":     nil,
+	}, tree)
+
+	tree, err = fieldsArg(map[string]any{FieldsArg: []string{"config", "config.mode"}})
+	require.NoError(t, err)
+	assert.Equal(t, fieldTree{"config": nil}, tree, "a whole field covers its subfields")
+
+	for _, v := range []any{"name", []any{"name", 1}, []any{""}, []any{"port_table[0]"}, []any{"a..b"}} {
+		_, err := fieldsArg(map[string]any{FieldsArg: v})
+		assert.Error(t, err, v)
+	}
+}
+
+func TestGenericList_Fields(t *testing.T) {
+	handler := GenericList(&fieldsTestClient{}, "Test")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{FieldsArg: []any{"name", "port_table[].port_idx", "config.mode", "missing"}}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.JSONEq(t, `[
+		{"name": "switch", "port_table": [{"port_idx": 1}, {"port_idx": 2}]},
+		{"name": "gateway", "config": {"mode": "router"}}
+	]`, result.Content[0].(mcp.TextContent).Text)
+
+	req.Params.Arguments = map[string]any{FieldsArg: []any{"name["}}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `invalid field path "name["`)
+}
+
+func TestGenericGet_Fields(t *testing.T) {
+	handler := GenericGet(&fieldsTestClient{}, "Test", false)
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"id": "2", FieldsArg: []any{"_id", "mac", "name.first"}}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.JSONEq(t, `{"_id": "2", "mac": "bb"}`, result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,526 @@
+package generated
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FilterArg is the argument that limits the output of list tools to the
+// objects matching an expression.
+const FilterArg = "filter"
+
+// filterExpr is a parsed filter expression.
+//
+// Expressions compare a field path with a literal, such as
+// vlan == 30 && purpose == "corporate". The operators are == and !=, the
+// numeric (or string) comparisons <, <=, > and >=, contains (substring or
+// array element), =~ and !~ (regular expression), combined with && (and),
+// || (or), ! (not) and parentheses. A path alone is true if the field is set
+// to anything but false, zero or empty. Paths are written as for the fields
+// argument; a path through an array marked with [], such as
+// port_table[].poe_enable == true, matches if any element matches. Missing
+// fields compare equal to null.
+type filterExpr interface {
+	match(item any) bool
+}
+
+type andExpr struct{ left, right filterExpr }
+
+func (e andExpr) match(item any) bool { return e.left.match(item) && e.right.match(item) }
+
+type orExpr struct{ left, right filterExpr }
+
+func (e orExpr) match(item any) bool { return e.left.match(item) || e.right.match(item) }
+
+type notExpr struct{ expr filterExpr }
+
+func (e notExpr) match(item any) bool { return !e.expr.match(item) }
+
+// truthyExpr matches if the path holds a value other than null, false, zero
+// or empty.
+type truthyExpr struct{ path []string }
+
+func (e truthyExpr) match(item any) bool {
+	for _, v := range resolvePath(item, e.path) {
+		if truthy(v) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareExpr struct {
+	path  []string
+	op    string
+	value any
+	re    *regexp.Regexp // for =~ and !~
+}
+
+func (e compareExpr) match(item any) bool {
+	values := resolvePath(item, e.path)
+	switch e.op {
+	case "!=":
+		return !anyValue(values, func(v any) bool { return reflect.DeepEqual(v, e.value) })
+	case "!~":
+		return !anyValue(values, e.matchRegexp)
+	}
+	return anyValue(values, func(v any) bool {
+		switch e.op {
+		case "==":
+			return reflect.DeepEqual(v, e.value)
+		case "=~":
+			return e.matchRegexp(v)
+		case "contains":
+			return contains(v, e.value)
+		default:
+			return compareOrdered(v, e.value, e.op)
+		}
+	})
+}
+
+func (e compareExpr) matchRegexp(v any) bool {
+	s, ok := v.(string)
+	return ok && e.re.MatchString(s)
+}
+
+func anyValue(values []any, pred func(any) bool) bool {
+	for _, v := range values {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the values a path selects in a decoded JSON value.
+// Segments marked with [] fan out over array elements. A missing field
+// yields a single nil.
+func resolvePath(v any, path []string) []any {
+	if len(path) == 0 {
+		return []any{v}
+	}
+	name := strings.TrimSuffix(path[0], "[]")
+	obj, _ := v.(map[string]any)
+	field := obj[name]
+	if name == path[0] {
+		return resolvePath(field, path[1:])
+	}
+	items, _ := field.([]any)
+	var out []any
+	for _, item := range items {
+		out = append(out, resolvePath(item, path[1:])...)
+	}
+	return out
+}
+
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
+
+// contains reports whether a string contains a substring, or an array an
+// element equal to the value.
+func contains(v, value any) bool {
+	switch v := v.(type) {
+	case string:
+		s, ok := value.(string)
+		return ok && strings.Contains(v, s)
+	case []any:
+		return anyValue(v, func(item any) bool { return reflect.DeepEqual(item, value) })
+	}
+	return false
+}
+
+// compareOrdered compares two numbers or two strings; other values never
+// match.
+func compareOrdered(a, b any, op string) bool {
+	var cmp int
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(a, b)
+	default:
+		return false
+	}
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// filterArg parses the filter argument. It returns nil if the argument is
+// absent or empty, matching everything.
+func filterArg(args map[string]any) (filterExpr, error) {
+	raw, ok := args[FilterArg]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a string", FilterArg)
+	}
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	expr, err := parseFilter(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FilterArg, err)
+	}
+	return expr, nil
+}
+
+// QueriedFields returns the field paths that the filter and sort arguments
+// read, as written, such as port_table[].poe_enable. A filter that does not
+// parse reads nothing; the list handler reports it.
+func QueriedFields(args map[string]any) []string {
+	var paths []string
+	if expr, err := filterArg(args); err == nil {
+		paths = appendFilterPaths(paths, expr)
+	}
+	if s, ok := args[SortArg].(string); ok {
+		if s = strings.TrimPrefix(strings.TrimSpace(s), "-"); s != "" {
+			paths = append(paths, s)
+		}
+	}
+	return paths
+}
+
+func appendFilterPaths(paths []string, expr filterExpr) []string {
+	switch e := expr.(type) {
+	case andExpr:
+		return appendFilterPaths(appendFilterPaths(paths, e.left), e.right)
+	case orExpr:
+		return appendFilterPaths(appendFilterPaths(paths, e.left), e.right)
+	case notExpr:
+		return appendFilterPaths(paths, e.expr)
+	case truthyExpr:
+		return append(paths, strings.Join(e.path, "."))
+	case compareExpr:
+		return append(paths, strings.Join(e.path, "."))
+	}
+	return paths
+}
+
+// applyFilter returns the elements of a decoded JSON array that match the
+// expression. Other values are returned unchanged.
+func applyFilter(v any, expr filterExpr) any {
+	items, ok := v.([]any)
+	if expr == nil || !ok {
+		return v
+	}
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		if expr.match(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Filter token kinds.
+const (
+	tokEOF = iota
+	tokPath
+	tokString
+	tokNumber
+	tokOp
+)
+
+type filterToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+// filterOps are the operators and punctuation of the filter language, longest
+// first.
+var filterOps = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")"}
+
+func lexFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	i := 0
+	for i < len(s) {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("at %d: %w", i+1, err)
+			}
+			tokens = append(tokens, filterToken{tokString, text, i})
+			i += n
+		case c == '-' || c == '.' || unicode.IsDigit(c):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || strings.ContainsRune(".eE+-", rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, filterToken{tokNumber, s[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] == '-' || s[j] == '.' || s[j] == '[' || s[j] == ']' ||
+				unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, filterToken{tokPath, s[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range filterOps {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("at %d: unexpected %q", i+1, c)
+			}
+			tokens = append(tokens, filterToken{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, filterToken{kind: tokEOF, pos: len(s)}), nil
+}
+
+// lexString reads a quoted string, returning its value and length. Within
+// the quotes a backslash escapes the quote or another backslash; other
+// backslashes are kept, so regular expressions such as \d work unchanged.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\') {
+				i++
+			}
+			b.WriteByte(s[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// parseFilter parses a filter expression, see filterExpr.
+func parseFilter(s string) (filterExpr, error) {
+	tokens, err := lexFilter(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+	return expr, nil
+}
+
+func (p *filterParser) peek() filterToken { return p.tokens[p.pos] }
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators or
+// keywords.
+func (p *filterParser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp && tok.kind != tokPath {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *filterParser) unexpected(tok filterToken) error {
+	if tok.kind == tokEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("at %d: unexpected %q", tok.pos+1, tok.text)
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, p.unexpected(p.peek())
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterExpr, error) {
+	tok := p.next()
+	if tok.kind != tokPath || isFilterKeyword(tok.text) {
+		return nil, p.unexpected(tok)
+	}
+	path := strings.Split(tok.text, ".")
+	for _, seg := range path {
+		if !fieldSegment.MatchString(seg) {
+			return nil, fmt.Errorf("at %d: invalid field path %q", tok.pos+1, tok.text)
+		}
+	}
+
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "=~", "!~", "contains")
+	if !ok {
+		return truthyExpr{path}, nil
+	}
+	valueTok := p.next()
+	value, err := filterValue(valueTok)
+	if err != nil {
+		return nil, err
+	}
+	expr := compareExpr{path: path, op: op, value: value}
+	if op == "=~" || op == "!~" {
+		pattern, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("at %d: %s needs a quoted regular expression", valueTok.pos+1, op)
+		}
+		if expr.re, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("at %d: %w", valueTok.pos+1, err)
+		}
+	}
+	return expr, nil
+}
+
+// filterValue returns the literal value of a token: a string, number, true,
+// false or null.
+func filterValue(tok filterToken) (any, error) {
+	switch tok.kind {
+	case tokString:
+		return tok.text, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("at %d: invalid number %q", tok.pos+1, tok.text)
+		}
+		return n, nil
+	case tokPath:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("at %d: expected a value, got %q (quote strings)", tok.pos+1, tok.text)
+	}
+	if tok.kind == tokEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("at %d: expected a value, got %q", tok.pos+1, tok.text)
+}
+
+func isFilterKeyword(s string) bool {
+	switch s {
+	case "and", "or", "not", "contains", "true", "false", "null":
+		return true
+	}
+	return false
+}
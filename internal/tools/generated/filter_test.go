@@ -0,0 +1,126 @@
+package generated
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filterTestNetworks = `[
+	{"name": "LAN", "purpose": "corporate", "vlan": 1, "enabled": true, "dhcpd_dns": ["1.1.1.1", "8.8.8.8"]},
+	{"name": "IoT", "purpose": "corporate", "vlan": 30, "enabled": true, "ports": [{"idx": 1, "poe": true}, {"idx": 2, "poe": false}]},
+	{"name": "Guest Wifi", "purpose": "guest", "vlan": 40, "enabled": false},
+	{"name": "WAN", "purpose": "wan"}
+]`
+
+func TestParseFilter(t *testing.T) {
+	var items []any
+	require.NoError(t, json.Unmarshal([]byte(filterTestNetworks), &items))
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`vlan == 30 && purpose == "corporate"`, []string{"IoT"}},
+		{`vlan == 30 and purpose == 'corporate'`, []string{"IoT"}},
+		{`purpose != 'corporate'`, []string{"Guest Wifi", "WAN"}},
+		{`vlan >= 30`, []string{"IoT", "Guest Wifi"}},
+		{`vlan < 30 || purpose == "wan"`, []string{"LAN", "WAN"}},
+		{`name contains "Wi"`, []string{"Guest Wifi"}},
+		{`dhcpd_dns contains "8.8.8.8"`, []string{"LAN"}},
+		{`name =~ "^(LAN|WAN)$"`, []string{"LAN", "WAN"}},
+		{`name !~ "(?i)wifi"`, []string{"LAN", "IoT", "WAN"}},
+		{`name =~ "\d"`, nil},
+		{`enabled`, []string{"LAN", "IoT"}},
+		{`!enabled`, []string{"Guest Wifi", "WAN"}},
+		{`not (enabled || purpose == 'wan')`, []string{"Guest Wifi"}},
+		{`vlan == null`, []string{"WAN"}},
+		{`ports[].poe == true`, []string{"IoT"}},
+		{`ports[].idx > 1 && vlan == 30`, []string{"IoT"}},
+		{`vlan > "10"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := parseFilter(tt.expr)
+			require.NoError(t, err)
+			var got []string
+			for _, item := range applyFilter(items, expr).([]any) {
+				got = append(got, item.(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := map[string]string{
+		`vlan ==`:                  "unexpected end of expression",
+		`vlan == corporate`:        `at 9: expected a value, got "corporate" (quote strings)`,
+		`(vlan == 1`:               "unexpected end of expression",
+		`vlan == 1 vlan`:           `at 11: unexpected "vlan"`,
+		`name == "LAN`:             "at 9: unterminated string",
+		`name =~ "("`:              "at 9: error parsing regexp",
+		`name =~ 5`:                "at 9: =~ needs a quoted regular expression",
+		`vlan = 1`:                 `at 6: unexpected '='`,
+		`port[0].idx == 1`:         `at 1: invalid field path "port[0].idx"`,
+		`== 1`:                     `at 1: unexpected "=="`,
+		`vlan == 1 && && vlan > 2`: `at 14: unexpected "&&"`,
+	}
+	for expr, want := range tests {
+		_, err := parseFilter(expr)
+		require.Error(t, err, expr)
+		assert.Contains(t, err.Error(), want, expr)
+	}
+}
+
+func TestFilterArg(t *testing.T) {
+	expr, err := filterArg(map[string]any{FilterArg: "  "})
+	require.NoError(t, err)
+	assert.Nil(t, expr)
+
+	_, err = filterArg(map[string]any{FilterArg: 30})
+	assert.EqualError(t, err, "filter must be a string")
+
+	_, err = filterArg(map[string]any{FilterArg: "vlan =="})
+	assert.EqualError(t, err, "invalid filter: unexpected end of expression")
+}
+
+func TestQueriedFields(t *testing.T) {
+	assert.Equal(t, []string{"vlan", "name", "port_table[].poe_enable", "x_passphrase", "radius.port"}, QueriedFields(map[string]any{
+		FilterArg: `vlan == 30 && (name contains "lab" || !port_table[].poe_enable) && x_passphrase =~ "^a"`,
+		SortArg:   "-radius.port",
+	}))
+	assert.Nil(t, QueriedFields(map[string]any{FilterArg: "vlan =="}))
+	assert.Nil(t, QueriedFields(nil))
+}
+
+func TestGenericList_Filter(t *testing.T) {
+	handler := GenericList(&fieldsTestClient{}, "Test")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		FilterArg: "port_table[].name == 'ap' || config.vlan > 5",
+		FieldsArg: []any{"name"},
+	}
+
+	result, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.JSONEq(t, `[{"name": "switch"}, {"name": "gateway"}]`, result.Content[0].(mcp.TextContent).Text)
+
+	req.Params.Arguments = map[string]any{FilterArg: "name == 'switch'"}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"port_table"`)
+	assert.NotContains(t, result.Content[0].(mcp.TextContent).Text, "gateway")
+
+	req.Params.Arguments = map[string]any{FilterArg: "name = 'switch'"}
+	result, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid filter")
+}
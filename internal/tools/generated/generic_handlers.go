@@ -3,6 +3,7 @@ package generated
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -14,12 +15,30 @@ import (
 
 // GenericList creates a handler that calls client.List<Resource>(ctx, site) via reflection.
 // The client parameter accepts any type (typically unifi.Client) and uses reflection
-// to call the appropriate method.
+// to call the appropriate method. The filter argument limits the output to the
+// objects matching an expression, and the fields argument to some fields of
+// each. With limit, offset or cursor the objects are returned a page at a
+// time, see pageRequest.
 func GenericList(client any, resourceName string) server.ToolHandlerFunc {
 	methodName := "List" + resourceName
 
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		site := extractSite(req)
+		filter, err := filterArg(req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		fields, err := fieldsArg(req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		page, err := pageArg(req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if page != nil && page.cursor != nil {
+			return page.resume(client, resourceName, site, fields)
+		}
 
 		clientVal := reflect.ValueOf(client)
 		method := clientVal.MethodByName(methodName)
@@ -36,21 +55,30 @@ func GenericList(client any, resourceName string) server.ToolHandlerFunc {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		data, err := json.MarshalIndent(results[0].Interface(), "", "  ")
+		if page == nil {
+			return readResult(results[0].Interface(), filter, fields)
+		}
+		decoded, err := decodeJSON(results[0].Interface())
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %v", err)), nil
 		}
-		return mcp.NewToolResultText(string(data)), nil
+		items, _ := applyFilter(decoded, filter).([]any)
+		return page.first(client, resourceName, site, items, fields)
 	}
 }
 
 // GenericGet creates a handler that calls client.Get<Resource>(ctx, site, id) via reflection.
 // For settings resources (isSetting=true), it calls client.Get<Resource>(ctx, site) without ID.
+// The fields argument limits the output to some fields of the object.
 func GenericGet(client any, resourceName string, isSetting bool) server.ToolHandlerFunc {
 	methodName := "Get" + resourceName
 
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		site := extractSite(req)
+		fields, err := fieldsArg(req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
 		clientVal := reflect.ValueOf(client)
 		method := clientVal.MethodByName(methodName)
@@ -81,11 +109,7 @@ func GenericGet(client any, resourceName string, isSetting bool) server.ToolHand
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		data, err := json.MarshalIndent(results[0].Interface(), "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %v", err)), nil
-		}
-		return mcp.NewToolResultText(string(data)), nil
+		return readResult(results[0].Interface(), nil, fields)
 	}
 }
 
@@ -102,6 +126,7 @@ func GenericCreate(client any, resourceName string, newTypeFunc func() any) serv
 		args := req.GetArguments()
 		allowedKeys := allowedFieldKeys(input)
 		allowedKeys["site"] = struct{}{}
+		allowedKeys[DryRunArg] = struct{}{}
 
 		if unexpected := unexpectedKeys(args, allowedKeys); len(unexpected) > 0 {
 			return mcp.NewToolResultError("unexpected parameters: " + strings.Join(unexpected, ", ")), nil
@@ -109,7 +134,7 @@ func GenericCreate(client any, resourceName string, newTypeFunc func() any) serv
 
 		dataMap := make(map[string]any)
 		for key, value := range args {
-			if key == "site" {
+			if isControlArg(key) {
 				continue
 			}
 			if _, ok := allowedKeys[key]; ok {
@@ -135,6 +160,10 @@ func GenericCreate(client any, resourceName string, newTypeFunc func() any) serv
 			return mcp.NewToolResultError(fmt.Sprintf("method %s not found", methodName)), nil
 		}
 
+		if isDryRun(args) {
+			return dryRunResult(DryRunPlan{Action: "create", Resource: resourceName, Site: site, Object: input})
+		}
+
 		results := method.Call([]reflect.Value{
 			reflect.ValueOf(ctx),
 			reflect.ValueOf(site),
@@ -166,6 +195,7 @@ func GenericUpdate(client any, resourceName string, newTypeFunc func() any, isSe
 		args := req.GetArguments()
 		allowedKeys := allowedFieldKeys(input)
 		allowedKeys["site"] = struct{}{}
+		allowedKeys[DryRunArg] = struct{}{}
 		if !isSetting {
 			allowedKeys["id"] = struct{}{}
 		}
@@ -176,7 +206,7 @@ func GenericUpdate(client any, resourceName string, newTypeFunc func() any, isSe
 
 		dataMap := make(map[string]any)
 		for key, value := range args {
-			if key == "site" || key == "id" {
+			if isControlArg(key) {
 				continue
 			}
 			if _, ok := allowedKeys[key]; ok {
@@ -197,24 +227,23 @@ func GenericUpdate(client any, resourceName string, newTypeFunc func() any, isSe
 			}
 		}
 
-		clientVal := reflect.ValueOf(client)
-		getMethod := clientVal.MethodByName("Get" + resourceName)
-		if !getMethod.IsValid() {
-			return mcp.NewToolResultError("missing client method: Get" + resourceName + " (required for updates)"), nil
-		}
-
-		getArgs := []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(site)}
-		if !isSetting {
-			getArgs = append(getArgs, reflect.ValueOf(id))
-		}
-		getResults := getMethod.Call(getArgs)
-		if err := extractError(getResults[1]); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
-		}
-		if isNilValue(getResults[0]) {
-			return mcp.NewToolResultError("failed to fetch existing resource"), nil
+		// The update is merged onto the snapshot taken earlier in the call,
+		// if any, so it applies to the state that was audited and journaled.
+		existing, ok := snapshotFromContext(ctx, resourceName, site, id)
+		if !ok {
+			current, err := FetchResource(ctx, client, resourceName, site, id)
+			if errors.Is(err, ErrNoGetter) {
+				return mcp.NewToolResultError("missing client method: Get" + resourceName + " (required for updates)"), nil
+			}
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if current == nil {
+				return mcp.NewToolResultError("failed to fetch existing resource"), nil
+			}
+			existing = current
 		}
-		existingRaw, err := json.Marshal(getResults[0].Interface())
+		existingRaw, err := json.Marshal(existing)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to parse existing resource: %v", err)), nil
 		}
@@ -241,11 +270,31 @@ func GenericUpdate(client any, resourceName string, newTypeFunc func() any, isSe
 			return mcp.NewToolResultError("invalid data: " + err.Error()), nil
 		}
 
-		method := clientVal.MethodByName(methodName)
+		method := reflect.ValueOf(client).MethodByName(methodName)
 		if !method.IsValid() {
 			return mcp.NewToolResultError(fmt.Sprintf("method %s not found", methodName)), nil
 		}
 
+		if isDryRun(args) {
+			// Compare what the controller has now with what would be sent,
+			// both normalized through the resource type.
+			var current, merged map[string]any
+			if err := json.Unmarshal(existingRaw, &current); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse existing resource: %v", err)), nil
+			}
+			if merged, err = toJSONMap(input); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal update: %v", err)), nil
+			}
+			return dryRunResult(DryRunPlan{
+				Action:   "update",
+				Resource: resourceName,
+				Site:     site,
+				ID:       id,
+				Object:   merged,
+				Changes:  DiffFields(current, merged),
+			})
+		}
+
 		results := method.Call([]reflect.Value{
 			reflect.ValueOf(ctx),
 			reflect.ValueOf(site),
@@ -270,7 +319,8 @@ func GenericDelete(client any, resourceName string) server.ToolHandlerFunc {
 
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		site := extractSite(req)
-		id, ok := req.GetArguments()["id"].(string)
+		args := req.GetArguments()
+		id, ok := args["id"].(string)
 		if !ok || id == "" {
 			return mcp.NewToolResultError("required parameter 'id' is missing or invalid"), nil
 		}
@@ -281,6 +331,15 @@ func GenericDelete(client any, resourceName string) server.ToolHandlerFunc {
 			return mcp.NewToolResultError(fmt.Sprintf("method %s not found", methodName)), nil
 		}
 
+		if isDryRun(args) {
+			// Show what would be removed when the resource can be fetched.
+			current, err := FetchResource(ctx, client, resourceName, site, id)
+			if err != nil && !errors.Is(err, ErrNoGetter) {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return dryRunResult(DryRunPlan{Action: "delete", Resource: resourceName, Site: site, ID: id, Object: current})
+		}
+
 		results := method.Call([]reflect.Value{
 			reflect.ValueOf(ctx),
 			reflect.ValueOf(site),
@@ -295,7 +354,36 @@ func GenericDelete(client any, resourceName string) server.ToolHandlerFunc {
 	}
 }
 
+// ErrNoGetter is returned by FetchResource when the client has no
+// Get<resource> method.
+var ErrNoGetter = errors.New("client cannot fetch resource")
+
+// FetchResource returns the current state of a resource through the client's
+// Get<resource> method. Settings are fetched by site alone and ignore id.
+func FetchResource(ctx context.Context, client any, resourceName, site, id string) (any, error) {
+	getMethod := reflect.ValueOf(client).MethodByName("Get" + resourceName)
+	if !getMethod.IsValid() {
+		return nil, fmt.Errorf("%w: missing method Get%s", ErrNoGetter, resourceName)
+	}
+
+	getArgs := []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(site)}
+	if getMethod.Type().NumIn() == 3 {
+		getArgs = append(getArgs, reflect.ValueOf(id))
+	}
+	results := getMethod.Call(getArgs)
+	if err := extractError(results[1]); err != nil {
+		return nil, err
+	}
+	if isNilValue(results[0]) {
+		return nil, nil
+	}
+	return results[0].Interface(), nil
+}
+
 // extractSite extracts the site parameter from the request, defaulting to "default".
+// Servers resolve the site argument (including the configured default site) in
+// middleware before handlers run, so the fallback only applies to handlers
+// invoked directly.
 func extractSite(req mcp.CallToolRequest) string {
 	site, _ := req.GetArguments()["site"].(string)
 	if site == "" {
@@ -304,6 +392,17 @@ func extractSite(req mcp.CallToolRequest) string {
 	return site
 }
 
+// isControlArg reports whether an argument steers the handler rather than
+// being a field of the resource.
+func isControlArg(key string) bool {
+	switch key {
+	case "site", "id", DryRunArg:
+		return true
+	default:
+		return false
+	}
+}
+
 func allowedFieldKeys(input any) map[string]struct{} {
 	keys := make(map[string]struct{})
 	inputType := reflect.TypeOf(input)
@@ -452,3 +551,36 @@ func ValidateClientMethods(client any, tools []ToolMetadata, typeRegistry map[st
 
 	return nil
 }
+
+// resourceSnapshot is the state of a resource fetched before a call changes it.
+type resourceSnapshot struct {
+	resource, site, id string
+	object             any
+}
+
+type snapshotKey struct{}
+
+// Snapshot returns the state of a resource before a call changes it, and a
+// context carrying it to the rest of the call. The resource is fetched with
+// FetchResource unless ctx already carries its snapshot, so the layers of a
+// call that need the previous state (audit, journal and GenericUpdate's
+// merge) read it from the controller once and all see the same object.
+func Snapshot(ctx context.Context, client any, resourceName, site, id string) (context.Context, any, error) {
+	if object, ok := snapshotFromContext(ctx, resourceName, site, id); ok {
+		return ctx, object, nil
+	}
+	object, err := FetchResource(ctx, client, resourceName, site, id)
+	if err != nil || object == nil {
+		return ctx, object, err
+	}
+	return context.WithValue(ctx, snapshotKey{}, resourceSnapshot{resource: resourceName, site: site, id: id, object: object}), object, nil
+}
+
+// snapshotFromContext returns the snapshot of a resource carried by ctx.
+func snapshotFromContext(ctx context.Context, resourceName, site, id string) (any, bool) {
+	s, ok := ctx.Value(snapshotKey{}).(resourceSnapshot)
+	if !ok || s.resource != resourceName || s.site != site || s.id != id {
+		return nil, false
+	}
+	return s.object, true
+}
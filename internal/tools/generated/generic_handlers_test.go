@@ -512,9 +512,11 @@ type mergeTestResource struct {
 
 type mergeUpdateClient struct {
 	updated *mergeTestResource
+	gets    int
 }
 
 func (c *mergeUpdateClient) GetTest(_ context.Context, _, id string) (any, error) {
+	c.gets++
 	return &mergeTestResource{ID: id, Name: "existing", Enabled: true}, nil
 }
 
@@ -788,3 +790,51 @@ func TestGenericDelete_WithFakeClient_Error(t *testing.T) {
 	content := result.Content[0].(mcp.TextContent)
 	assert.Contains(t, content.Text, "delete error")
 }
+
+func TestFetchResource(t *testing.T) {
+	obj, err := FetchResource(context.Background(), &FakeTestClient{}, "Test", "default", "123")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "123", "name": "test"}, obj)
+
+	// Settings are fetched by site only.
+	obj, err = FetchResource(context.Background(), &FakeTestClient{}, "TestSetting", "default", "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"enabled": "true"}, obj)
+
+	_, err = FetchResource(context.Background(), &FakeTestClient{ShouldError: true}, "Test", "default", "123")
+	assert.EqualError(t, err, "get error")
+
+	_, err = FetchResource(context.Background(), &FakeTestClient{}, "Missing", "default", "123")
+	assert.ErrorIs(t, err, ErrNoGetter)
+}
+
+func TestSnapshot(t *testing.T) {
+	client := &mergeUpdateClient{}
+	ctx, before, err := Snapshot(context.Background(), client, "Test", "default", "123")
+	require.NoError(t, err)
+	assert.Equal(t, &mergeTestResource{ID: "123", Name: "existing", Enabled: true}, before)
+
+	// Later layers of the call get the same object without another read.
+	_, again, err := Snapshot(ctx, client, "Test", "default", "123")
+	require.NoError(t, err)
+	assert.Same(t, before, again)
+	assert.Equal(t, 1, client.gets)
+
+	// The update is merged onto the snapshot.
+	before.(*mergeTestResource).Enabled = false
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"site": "default", "id": "123", "name": "updated item"}
+	result, err := GenericUpdate(client, "Test", func() any { return &mergeTestResource{} }, false)(ctx, req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, 1, client.gets)
+	assert.False(t, client.updated.Enabled)
+
+	// A snapshot of another resource is not used.
+	_, _, err = Snapshot(ctx, client, "Test", "default", "456")
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.gets)
+
+	_, _, err = Snapshot(context.Background(), &FakeTestClient{ShouldError: true}, "Test", "default", "123")
+	assert.EqualError(t, err, "get error")
+}
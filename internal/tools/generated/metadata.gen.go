@@ -24,7 +24,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -39,12 +66,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -59,7 +91,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -96,12 +132,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -138,12 +178,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -158,7 +202,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -173,12 +244,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -193,7 +269,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -261,12 +341,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -334,12 +418,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -354,7 +442,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -369,12 +484,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -389,7 +509,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -426,12 +550,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -468,12 +596,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -488,7 +620,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -503,12 +662,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -523,7 +687,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"ap_blacklisted_channels": map[string]any{
 					"type":  "array",
@@ -596,12 +764,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"ap_blacklisted_channels": map[string]any{
 					"type":  "array",
 					"items": map[string]any{"type": "object"},
@@ -674,12 +846,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -694,7 +870,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -709,12 +912,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -729,7 +937,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -778,12 +990,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -832,12 +1048,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -852,7 +1072,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -867,12 +1114,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -887,7 +1139,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -949,12 +1205,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -1016,12 +1276,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1036,7 +1300,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -1051,12 +1342,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1071,7 +1367,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -1117,12 +1417,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -1168,12 +1472,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1188,7 +1496,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -1203,12 +1538,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1223,7 +1563,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -1238,12 +1605,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1258,7 +1630,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -1323,12 +1699,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -1393,12 +1773,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1413,7 +1797,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -1428,12 +1839,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1448,7 +1864,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -1491,12 +1911,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -1539,12 +1963,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1559,7 +1987,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -1574,12 +2029,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1594,7 +2054,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"action": map[string]any{
 					"type":        "string",
@@ -1738,12 +2202,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"action": map[string]any{
 					"type":        "string",
 					"description": "One of: drop|reject|accept",
@@ -1887,12 +2355,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1907,7 +2379,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -1922,12 +2421,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -1942,7 +2446,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -1979,12 +2487,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -2021,12 +2533,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2041,7 +2557,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -2056,12 +2599,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2076,7 +2624,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"action": map[string]any{
 					"type":        "string",
@@ -2174,12 +2726,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"action": map[string]any{
 					"type":        "string",
 					"description": "One of: ALLOW|BLOCK|REJECT",
@@ -2277,12 +2833,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2297,7 +2857,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -2312,12 +2899,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2332,7 +2924,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -2377,12 +2973,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -2427,12 +3027,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2447,7 +3051,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -2462,12 +3093,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2482,7 +3118,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -2527,12 +3167,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -2577,12 +3221,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2597,7 +3245,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -2612,12 +3287,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -2632,7 +3312,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"anqp_domain_id": map[string]any{
 					"type":    "integer",
@@ -2843,12 +3527,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"anqp_domain_id": map[string]any{
 					"type":    "integer",
 					"pattern": "^0|[1-9][0-9]{0,3}|[1-5][0-9]{4}|6[0-4][0-9]{3}|65[0-4][0-9]{2}|655[0-2][0-9]|6553[0-5]|$",
@@ -3059,12 +3747,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3079,7 +3771,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -3094,12 +3813,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3114,7 +3838,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -3155,12 +3883,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -3201,12 +3933,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3221,7 +3957,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -3236,12 +3999,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3256,7 +4024,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"amount": map[string]any{
 					"type": "number",
@@ -3374,12 +4146,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"amount": map[string]any{
 					"type": "number",
 				},
@@ -3497,12 +4273,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3517,7 +4297,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -3532,12 +4339,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3552,7 +4364,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -3630,12 +4446,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -3713,12 +4533,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3733,7 +4557,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -3748,12 +4599,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3768,7 +4624,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -3801,12 +4661,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -3839,12 +4703,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3859,7 +4727,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -3874,12 +4769,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -3894,7 +4794,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -4796,12 +5700,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -5703,12 +6611,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -5723,7 +6635,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -5738,12 +6677,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -5758,7 +6702,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -5843,12 +6791,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -5933,12 +6885,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -5953,7 +6909,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -5968,12 +6951,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -5988,7 +6976,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -6171,12 +7163,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -6359,12 +7355,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6379,7 +7379,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -6394,12 +7421,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6414,7 +7446,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"accounting_enabled": map[string]any{
 					"type": "boolean",
@@ -6502,12 +7538,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"accounting_enabled": map[string]any{
 					"type": "boolean",
 				},
@@ -6595,12 +7635,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6615,7 +7659,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -6630,12 +7701,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6650,7 +7726,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -6721,12 +7801,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -6797,12 +7881,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6817,7 +7905,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -6832,12 +7947,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6852,7 +7972,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"action": map[string]any{
 					"type":    "string",
@@ -6899,12 +8023,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"action": map[string]any{
 					"type":    "string",
 					"pattern": "upgrade",
@@ -6951,12 +8079,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -6972,7 +8104,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -6988,7 +8125,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7028,7 +8169,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7044,7 +8190,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7090,7 +8240,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7106,7 +8261,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7162,7 +8321,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7178,7 +8342,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7230,7 +8398,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7246,7 +8419,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7283,7 +8460,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7299,7 +8481,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7342,7 +8528,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7358,7 +8549,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7405,7 +8600,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7421,7 +8621,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7461,7 +8665,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7477,7 +8686,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7520,7 +8733,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7536,7 +8754,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7578,7 +8800,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7594,7 +8821,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7633,7 +8864,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7649,7 +8885,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"6e_channel_size": map[string]any{
 					"type":        "integer",
@@ -7730,7 +8970,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7746,7 +8991,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -7789,7 +9038,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7805,7 +9059,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"acl_device_isolation": map[string]any{
 					"type":  "array",
@@ -7876,7 +9134,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -7892,7 +9155,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"allowed_subnet": map[string]any{
 					"type": "string",
@@ -8253,7 +9520,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8269,7 +9541,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"ad_blocking_configurations": map[string]any{
 					"type":  "array",
@@ -8353,7 +9629,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8369,7 +9650,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8420,7 +9705,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8436,7 +9726,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8473,7 +9767,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8489,7 +9788,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8526,7 +9829,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8542,7 +9850,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"advanced_feature_enabled": map[string]any{
 					"type": "boolean",
@@ -8641,7 +9953,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8657,7 +9974,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8733,7 +10054,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8749,7 +10075,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8786,7 +10116,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8802,7 +10137,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8853,7 +10192,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8869,7 +10213,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -8906,7 +10254,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -8922,7 +10275,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9024,7 +10381,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9040,7 +10402,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"accounting_enabled": map[string]any{
 					"type": "boolean",
@@ -9102,7 +10468,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9118,7 +10489,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9190,7 +10565,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9206,7 +10586,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9258,7 +10642,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9274,7 +10663,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9313,7 +10706,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9329,7 +10727,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9384,7 +10786,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9400,7 +10807,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"_ignored": map[string]any{
 					"type": "string",
@@ -9437,7 +10848,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9453,7 +10869,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9500,7 +10920,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9516,7 +10941,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9556,7 +10985,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9572,7 +11006,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9611,7 +11049,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9627,7 +11070,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"analytics_disapproved_for": map[string]any{
 					"type": "string",
@@ -9814,7 +11261,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9830,7 +11282,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9882,7 +11338,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9898,7 +11359,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -9960,7 +11425,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -9976,7 +11446,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -10017,7 +11491,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -10033,7 +11512,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"arp_cache_base_reachable": map[string]any{
 					"type":    "integer",
@@ -10256,7 +11739,12 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
 				},
 			},
 		},
@@ -10272,7 +11760,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -10308,7 +11800,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -10323,12 +11842,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10343,7 +11867,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -10381,12 +11909,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -10424,12 +11956,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10444,7 +11980,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -10459,12 +12022,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10479,7 +12047,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -10516,12 +12088,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -10558,12 +12134,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10578,7 +12158,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -10593,12 +12200,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10613,7 +12225,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -10701,12 +12317,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -10794,12 +12414,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10814,7 +12438,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -10829,12 +12480,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10849,7 +12505,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -10891,12 +12551,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -10938,12 +12602,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10958,7 +12626,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -10973,12 +12668,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -10993,7 +12693,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -11043,12 +12747,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -11098,12 +12806,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -11118,7 +12830,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -11133,12 +12872,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -11153,7 +12897,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"ap_group_ids": map[string]any{
 					"type":  "array",
@@ -11516,12 +13264,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"ap_group_ids": map[string]any{
 					"type":  "array",
 					"items": map[string]any{"type": "string"},
@@ -11884,12 +13636,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -11904,7 +13660,34 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Return only items matching an expression such as vlan == 30 && purpose == 'corporate' (operators: == != < <= > >= contains =~ !~ && || !)",
+				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"description": "Return at most this many items, with total and next_cursor (default: all)",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Skip this many items",
+				},
+				"cursor": map[string]any{
+					"type":        "string",
+					"description": "next_cursor from the previous page, to continue the same listing",
+				},
+				"sort": map[string]any{
+					"type":        "string",
+					"description": "Field to sort by, prefixed with - for descending (default: _id when paging)",
 				},
 			},
 		},
@@ -11919,12 +13702,17 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"fields": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Return only these fields, as paths such as name, mac or port_table[].port_idx (default: all fields)",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -11939,7 +13727,11 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
+				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
 				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
@@ -11973,12 +13765,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 				"attr_hidden": map[string]any{
 					"type": "boolean",
 				},
@@ -12012,12 +13808,16 @@ var AllToolMetadata = []ToolMetadata{
 			"properties": map[string]any{
 				"site": map[string]any{
 					"type":        "string",
-					"description": "UniFi site name (default: 'default')",
+					"description": "UniFi site name or description (default: the server's configured site)",
 				},
 				"id": map[string]any{
 					"type":        "string",
 					"description": "Resource ID to delete",
 				},
+				"dry_run": map[string]any{
+					"type":        "boolean",
+					"description": "Preview the change without applying it",
+				},
 			},
 			"required": []any{"id"},
 		},
@@ -0,0 +1,327 @@
+package generated
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Arguments that page and sort the output of list tools.
+const (
+	LimitArg  = "limit"
+	OffsetArg = "offset"
+	CursorArg = "cursor"
+	SortArg   = "sort"
+)
+
+// SnapshotTTL is how long the objects behind a cursor are kept, so every page
+// of a listing comes from the same read of the controller.
+const SnapshotTTL = 5 * time.Minute
+
+// maxSnapshots bounds the memory held for cursors; the oldest go first.
+const maxSnapshots = 64
+
+// defaultSortKey orders paged results when no sort is given.
+const defaultSortKey = "_id"
+
+// listPage is the result of a paged list call.
+type listPage struct {
+	Items      any    `json:"items"`
+	Total      int    `json:"total"` // objects matching the filter, on all pages
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// pageRequest holds the paging and sort arguments of a list call. A paged
+// call returns a listPage of up to limit objects starting at offset, sorted by
+// the sort field (descending with a leading -) and then by ID. If more objects
+// follow, they are kept for SnapshotTTL and next_cursor reads the next page
+// from them, so the pages stay consistent while the controller changes.
+// A cursor carries the filter and sort of the first page.
+type pageRequest struct {
+	paged  bool // limit, offset or cursor given, so the result is a listPage
+	limit  int  // 0 for all remaining objects
+	offset int
+	cursor *pageCursor
+	sort   []string // field path, nil for the default
+	desc   bool
+}
+
+// pageCursor is the decoded form of a cursor.
+type pageCursor struct {
+	Snapshot string `json:"s"`
+	Offset   int    `json:"o"`
+	Limit    int    `json:"l,omitempty"`
+}
+
+// pageArg parses the paging and sort arguments. It returns nil if there are
+// none.
+func pageArg(args map[string]any) (*pageRequest, error) {
+	var p pageRequest
+	var err error
+	var hasLimit, hasOffset bool
+	if p.limit, hasLimit, err = intArg(args, LimitArg, 1); err != nil {
+		return nil, err
+	}
+	if p.offset, hasOffset, err = intArg(args, OffsetArg, 0); err != nil {
+		return nil, err
+	}
+	if raw, ok := args[CursorArg]; ok && raw != nil && raw != "" {
+		s, _ := raw.(string)
+		cursor, err := decodeCursor(s)
+		if err != nil {
+			return nil, err
+		}
+		if hasOffset {
+			return nil, fmt.Errorf("%s and %s cannot be used together", CursorArg, OffsetArg)
+		}
+		p.cursor = cursor
+		p.offset = cursor.Offset
+		if !hasLimit {
+			p.limit = cursor.Limit
+		}
+	}
+	if raw, ok := args[SortArg]; ok && raw != nil && raw != "" {
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", SortArg)
+		}
+		s = strings.TrimSpace(s)
+		if strings.HasPrefix(s, "-") {
+			p.desc = true
+			s = s[1:]
+		}
+		p.sort = strings.Split(s, ".")
+		for _, seg := range p.sort {
+			if !fieldSegment.MatchString(seg) {
+				return nil, fmt.Errorf("invalid %s field %q", SortArg, raw)
+			}
+		}
+	}
+	p.paged = hasLimit || hasOffset || p.cursor != nil
+	if !p.paged && p.sort == nil {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// intArg returns an integer argument of at least minValue.
+func intArg(args map[string]any, name string, minValue int) (int, bool, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+	var f float64
+	switch v := raw.(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	default:
+		return 0, false, fmt.Errorf("%s must be an integer", name)
+	}
+	if f != math.Trunc(f) || f < float64(minValue) || f > math.MaxInt32 {
+		return 0, false, fmt.Errorf("%s must be an integer of at least %d", name, minValue)
+	}
+	return int(f), true, nil
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	var c pageCursor
+	if err != nil || json.Unmarshal(data, &c) != nil || c.Snapshot == "" || c.Offset < 0 || c.Limit < 0 {
+		return nil, errors.New("invalid cursor: pass next_cursor from the previous page unchanged")
+	}
+	return &c, nil
+}
+
+// snapshot holds the filtered and sorted objects of a listing while its
+// pages are read.
+type snapshot struct {
+	client   any
+	resource string
+	site     string
+	items    []any
+	expires  time.Time
+}
+
+type snapshotStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	snapshots map[string]*snapshot
+}
+
+// snapshots is shared by all list handlers, since handlers are created anew
+// for calls through execute and batch.
+var snapshots = &snapshotStore{now: time.Now, snapshots: make(map[string]*snapshot)}
+
+// put stores a snapshot and returns its ID.
+func (s *snapshotStore) put(snap *snapshot) string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	id := hex.EncodeToString(b[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	snap.expires = now.Add(SnapshotTTL)
+	for k, other := range s.snapshots {
+		if !now.Before(other.expires) {
+			delete(s.snapshots, k)
+		}
+	}
+	for len(s.snapshots) >= maxSnapshots {
+		var oldest string
+		for k, other := range s.snapshots {
+			if oldest == "" || other.expires.Before(s.snapshots[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(s.snapshots, oldest)
+	}
+	s.snapshots[id] = snap
+	return id
+}
+
+// get returns a snapshot that has not expired.
+func (s *snapshotStore) get(id string) (*snapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[id]
+	if !ok || !s.now().Before(snap.expires) {
+		delete(s.snapshots, id)
+		return nil, false
+	}
+	return snap, true
+}
+
+// resume returns the next page of the listing a cursor points to.
+func (p *pageRequest) resume(client any, resourceName, site string, fields fieldTree) (*mcp.CallToolResult, error) {
+	snap, ok := snapshots.get(p.cursor.Snapshot)
+	if !ok {
+		return mcp.NewToolResultError("cursor expired; list again without a cursor"), nil
+	}
+	if snap.client != client || snap.resource != resourceName || snap.site != site {
+		return mcp.NewToolResultError("cursor belongs to a different listing"), nil
+	}
+	return p.render(snap.items, p.cursor.Snapshot, fields)
+}
+
+// first sorts the objects of a fresh listing and returns the requested page,
+// keeping a snapshot of them if there are more pages.
+func (p *pageRequest) first(client any, resourceName, site string, items []any, fields fieldTree) (*mcp.CallToolResult, error) {
+	sortPath, desc := p.sort, p.desc
+	if sortPath == nil {
+		sortPath = []string{defaultSortKey}
+	}
+	sortItems(items, sortPath, desc)
+	if !p.paged {
+		return renderJSON(items, fields)
+	}
+	var snapshotID string
+	if p.limit > 0 && p.offset+p.limit < len(items) {
+		snapshotID = snapshots.put(&snapshot{client: client, resource: resourceName, site: site, items: items})
+	}
+	return p.render(items, snapshotID, fields)
+}
+
+// render returns the page of items at the request's offset, with a cursor
+// into the snapshot for the next page if there is one.
+func (p *pageRequest) render(items []any, snapshotID string, fields fieldTree) (*mcp.CallToolResult, error) {
+	start := min(p.offset, len(items))
+	end := len(items)
+	if p.limit > 0 {
+		end = min(start+p.limit, end)
+	}
+	page := listPage{Items: items[start:end], Total: len(items), Offset: start}
+	if end < len(items) {
+		page.NextCursor = encodeCursor(pageCursor{Snapshot: snapshotID, Offset: end, Limit: p.limit})
+	}
+	if projected, ok := fields.project(page.Items); ok {
+		page.Items = projected
+	}
+	return renderJSON(page, nil)
+}
+
+// sortItems sorts decoded objects by the value at path, then by ID so the
+// order is the same on every read. Missing values sort first.
+func sortItems(items []any, path []string, desc bool) {
+	key := func(item any) any {
+		if values := resolvePath(item, path); len(values) > 0 {
+			return values[0]
+		}
+		return nil
+	}
+	id := func(item any) any { return resolvePath(item, []string{defaultSortKey})[0] }
+	sort.SliceStable(items, func(i, j int) bool {
+		cmp := compareValues(key(items[i]), key(items[j]))
+		if desc {
+			cmp = -cmp
+		}
+		if cmp == 0 {
+			cmp = compareValues(id(items[i]), id(items[j]))
+		}
+		return cmp < 0
+	})
+}
+
+// compareValues orders decoded JSON values: null, then false and true, then
+// numbers, then strings, then anything else by its JSON text.
+func compareValues(a, b any) int {
+	rank := func(v any) int {
+		switch v.(type) {
+		case nil:
+			return 0
+		case bool:
+			return 1
+		case float64:
+			return 2
+		case string:
+			return 3
+		}
+		return 4
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	switch a := a.(type) {
+	case nil:
+		return 0
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case !a:
+			return -1
+		}
+		return 1
+	case float64:
+		switch b := b.(float64); {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(a, b.(string))
+	}
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return strings.Compare(string(ja), string(jb))
+}
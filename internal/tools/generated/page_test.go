@@ -0,0 +1,162 @@
+package generated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageTestUser struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+	Rank int    `json:"rank,omitempty"`
+}
+
+// pageTestClient lists users; each call returns one more than the last, as
+// on a busy site.
+type pageTestClient struct {
+	users []pageTestUser
+	calls int
+}
+
+func (c *pageTestClient) ListTest(_ context.Context, _ string) ([]pageTestUser, error) {
+	c.calls++
+	c.users = append(c.users, pageTestUser{ID: fmt.Sprintf("u%02d", len(c.users)), Name: fmt.Sprintf("user %d", len(c.users))})
+	return c.users, nil
+}
+
+func listPageCall(t *testing.T, client any, args map[string]any) listPage {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := GenericList(client, "Test")(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content)
+	var page listPage
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &page))
+	return page
+}
+
+func pageIDs(page listPage) []string {
+	var ids []string
+	for _, item := range page.Items.([]any) {
+		ids = append(ids, item.(map[string]any)["_id"].(string))
+	}
+	return ids
+}
+
+func TestGenericList_Pages(t *testing.T) {
+	client := &pageTestClient{}
+	for range 4 {
+		_, _ = client.ListTest(context.Background(), "default")
+	}
+	client.calls = 0
+
+	page := listPageCall(t, client, map[string]any{LimitArg: float64(2), "site": "default"})
+	assert.Equal(t, []string{"u00", "u01"}, pageIDs(page))
+	assert.Equal(t, 5, page.Total)
+	assert.Zero(t, page.Offset)
+	require.NotEmpty(t, page.NextCursor)
+
+	// Later pages come from the snapshot, not the controller, which now has
+	// another user.
+	page = listPageCall(t, client, map[string]any{CursorArg: page.NextCursor, "site": "default", FieldsArg: []any{"_id"}})
+	assert.Equal(t, []string{"u02", "u03"}, pageIDs(page))
+	assert.Equal(t, []any{map[string]any{"_id": "u02"}, map[string]any{"_id": "u03"}}, page.Items)
+	assert.Equal(t, 2, page.Offset)
+	page = listPageCall(t, client, map[string]any{CursorArg: page.NextCursor, "site": "default"})
+	assert.Equal(t, []string{"u04"}, pageIDs(page))
+	assert.Equal(t, 5, page.Total)
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, 1, client.calls)
+
+	// Offset without limit returns the rest.
+	page = listPageCall(t, client, map[string]any{OffsetArg: float64(4)})
+	assert.Equal(t, []string{"u04", "u05"}, pageIDs(page))
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestGenericList_Sort(t *testing.T) {
+	client := &fieldsTestClient{}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{SortArg: "-name", FieldsArg: []any{"name"}}
+	result, err := GenericList(client, "Test")(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.JSONEq(t, `[{"name": "switch"}, {"name": "gateway"}]`, result.Content[0].(mcp.TextContent).Text)
+
+	items := []any{
+		map[string]any{"_id": "c", "rank": 2.0},
+		map[string]any{"_id": "b"},
+		map[string]any{"_id": "a", "rank": 2.0},
+		map[string]any{"_id": "d", "rank": "x"},
+		map[string]any{"_id": "e", "rank": 1.0},
+	}
+	sortItems(items, []string{"rank"}, false)
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.(map[string]any)["_id"].(string))
+	}
+	assert.Equal(t, []string{"b", "e", "a", "c", "d"}, ids)
+}
+
+func TestGenericList_PageErrors(t *testing.T) {
+	client := &pageTestClient{}
+	other := &pageTestClient{}
+	listPageCall(t, client, map[string]any{LimitArg: float64(1)})
+	page := listPageCall(t, client, map[string]any{LimitArg: float64(1)})
+	require.NotEmpty(t, page.NextCursor)
+
+	tests := []struct {
+		client any
+		args   map[string]any
+		want   string
+	}{
+		{client, map[string]any{LimitArg: float64(0)}, "limit must be an integer of at least 1"},
+		{client, map[string]any{LimitArg: 1.5}, "limit must be an integer of at least 1"},
+		{client, map[string]any{OffsetArg: "2"}, "offset must be an integer"},
+		{client, map[string]any{CursorArg: "not a cursor"}, "invalid cursor"},
+		{client, map[string]any{CursorArg: page.NextCursor, OffsetArg: float64(1)}, "cursor and offset cannot be used together"},
+		{client, map[string]any{CursorArg: page.NextCursor, "site": "branch"}, "cursor belongs to a different listing"},
+		{other, map[string]any{CursorArg: page.NextCursor}, "cursor belongs to a different listing"},
+		{client, map[string]any{CursorArg: encodeCursor(pageCursor{Snapshot: "gone", Offset: 1})}, "cursor expired"},
+		{client, map[string]any{SortArg: "rank[0]"}, "invalid sort field"},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tt.args
+		result, err := GenericList(tt.client, "Test")(context.Background(), req)
+		require.NoError(t, err)
+		require.True(t, result.IsError, tt.args)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.want, tt.args)
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	now := time.Now()
+	store := &snapshotStore{now: func() time.Time { return now }, snapshots: make(map[string]*snapshot)}
+
+	first := store.put(&snapshot{resource: "first"})
+	_, ok := store.get(first)
+	assert.True(t, ok)
+	now = now.Add(SnapshotTTL)
+	_, ok = store.get(first)
+	assert.False(t, ok, "snapshots expire")
+
+	ids := make([]string, maxSnapshots+1)
+	for i := range ids {
+		now = now.Add(time.Millisecond)
+		ids[i] = store.put(&snapshot{})
+	}
+	assert.Len(t, store.snapshots, maxSnapshots)
+	_, ok = store.get(ids[0])
+	assert.False(t, ok, "the oldest snapshot is dropped")
+	_, ok = store.get(ids[maxSnapshots])
+	assert.True(t, ok)
+}
@@ -0,0 +1,26 @@
+// Code generated by mcpgen. DO NOT EDIT.
+
+package generated
+
+// SecretFields maps resource names to the fields that hold credentials, such
+// as passphrases, RADIUS secrets and SSH passwords. A name matches the field
+// at any depth of the resource's objects.
+var SecretFields = map[string][]string{
+	"Account":                 {"x_password"},
+	"Device":                  {"lte_password", "x_baresip_password"},
+	"DynamicDNS":              {"x_password"},
+	"HotspotOp":               {"x_password"},
+	"Network":                 {"x_auth_key", "x_ca_crt", "x_ca_key", "x_dh_key", "x_ipsec_pre_shared_key", "x_openvpn_password", "x_openvpn_shared_secret_key", "x_pptpc_password", "x_server_crt", "x_server_key", "x_shared_client_crt", "x_shared_client_key", "x_wan_password", "x_wireguard_private_key"},
+	"RADIUSProfile":           {"x_ca_crt", "x_client_crt", "x_client_crt_filename", "x_client_private_key", "x_client_private_key_filename", "x_client_private_key_password", "x_secret"},
+	"SettingConnectivity":     {"x_mesh_essid", "x_mesh_psk"},
+	"SettingElementAdopt":     {"x_element_essid", "x_element_psk"},
+	"SettingGuestAccess":      {"x_authorize_loginid", "x_authorize_transactionkey", "x_facebook_app_secret", "x_facebook_wifi_gw_secret", "x_google_client_secret", "x_ippay_terminalid", "x_merchantwarrior_apikey", "x_merchantwarrior_apipassphrase", "x_merchantwarrior_merchantuuid", "x_password", "x_paypal_password", "x_paypal_signature", "x_paypal_username", "x_quickpay_agreementid", "x_quickpay_apikey", "x_quickpay_merchantid", "x_stripe_api_key", "x_wechat_app_secret", "x_wechat_secret_key"},
+	"SettingMgmt":             {"x_mgmt_key", "x_ssh_md5passwd", "x_ssh_password", "x_ssh_sha512passwd", "x_ssh_username"},
+	"SettingRadius":           {"x_secret"},
+	"SettingSnmp":             {"x_password"},
+	"SettingSuperCloudaccess": {"x_certificate_arn", "x_certificate_pem", "x_private_key"},
+	"SettingSuperMgmt":        {"autobackup_s3_access_secret", "x_ssh_password", "x_ssh_username"},
+	"SettingSuperSdn":         {"auth_token"},
+	"SettingSuperSmtp":        {"x_password"},
+	"WLAN":                    {"password", "psk", "x_iapp_key", "x_passphrase", "x_wep"},
+}
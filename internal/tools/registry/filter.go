@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+)
+
+// ToolFilter selects tools by name, category or resource. Patterns are
+// case-insensitive globs with an optional field prefix:
+//
+//	list_*              tool name (the default field)
+//	category:delete     tool category
+//	resource:Hotspot*   tool resource
+//
+// A tool is kept when it matches at least one include pattern (or there are
+// none) and matches no exclude pattern.
+type ToolFilter struct {
+	include ToolPatterns
+	exclude ToolPatterns
+}
+
+// ToolPatterns is a parsed list of tool patterns in the ToolFilter syntax.
+type ToolPatterns []toolPattern
+
+type toolPattern struct {
+	field string
+	glob  string
+}
+
+// NewToolFilter parses include and exclude patterns.
+func NewToolFilter(include, exclude []string) (*ToolFilter, error) {
+	f := &ToolFilter{}
+	var err error
+	if f.include, err = ParseToolPatterns(include); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if f.exclude, err = ParseToolPatterns(exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	return f, nil
+}
+
+// ParseToolPatterns parses patterns in the ToolFilter syntax.
+func ParseToolPatterns(patterns []string) (ToolPatterns, error) {
+	parsed := make(ToolPatterns, 0, len(patterns))
+	for _, p := range patterns {
+		field, glob, ok := strings.Cut(p, ":")
+		if !ok {
+			field, glob = "name", p
+		}
+		field = strings.ToLower(field)
+		switch field {
+		case "name", "category", "resource":
+		default:
+			return nil, fmt.Errorf("%q: unknown field %q (expected name, category or resource)", p, field)
+		}
+		glob = strings.ToLower(glob)
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("%q: %w", p, err)
+		}
+		parsed = append(parsed, toolPattern{field: field, glob: glob})
+	}
+	return parsed, nil
+}
+
+// Match reports whether the filter keeps the tool.
+func (f *ToolFilter) Match(meta generated.ToolMetadata) bool {
+	if len(f.include) > 0 && !f.include.Match(meta) {
+		return false
+	}
+	return !f.exclude.Match(meta)
+}
+
+// Match reports whether any of the patterns matches the tool.
+func (ps ToolPatterns) Match(meta generated.ToolMetadata) bool {
+	for _, p := range ps {
+		var value string
+		switch p.field {
+		case "category":
+			value = meta.Category
+		case "resource":
+			value = meta.Resource
+		default:
+			value = meta.Name
+		}
+		// Patterns are validated when parsed, so Match cannot fail here.
+		if ok, _ := path.Match(p.glob, strings.ToLower(value)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns a toolset containing only the tools the filter keeps.
+// Hidden tools are removed from the handlers as well, so they cannot be
+// called through execute or batch either.
+func (t Toolset) Filter(f *ToolFilter) Toolset {
+	tools := make([]generated.ToolMetadata, 0, len(t.Tools))
+	handlers := make(map[string]generated.HandlerFunc, len(t.Handlers))
+	for name, factory := range t.Handlers {
+		handlers[name] = factory
+	}
+
+	for _, meta := range t.Tools {
+		if f.Match(meta) {
+			tools = append(tools, meta)
+		} else {
+			delete(handlers, meta.Name)
+		}
+	}
+
+	return Toolset{Tools: tools, Handlers: handlers, Controllers: t.Controllers}
+}
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var filterTestTools = []generated.ToolMetadata{
+	{Name: "list_network", Category: "list", Resource: "Network"},
+	{Name: "delete_network", Category: "delete", Resource: "Network"},
+	{Name: "list_firewall_rule", Category: "list", Resource: "FirewallRule"},
+	{Name: "list_hotspot_op", Category: "list", Resource: "HotspotOp"},
+	{Name: "list_heat_map", Category: "list", Resource: "HeatMap"},
+	{Name: "get_spatial_record", Category: "get", Resource: "SpatialRecord"},
+}
+
+func filteredNames(t *testing.T, include, exclude []string) []string {
+	t.Helper()
+	f, err := NewToolFilter(include, exclude)
+	require.NoError(t, err)
+
+	var names []string
+	for _, meta := range filterTestTools {
+		if f.Match(meta) {
+			names = append(names, meta.Name)
+		}
+	}
+	return names
+}
+
+func TestToolFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			name: "no patterns keeps everything",
+			expected: []string{
+				"list_network", "delete_network", "list_firewall_rule",
+				"list_hotspot_op", "list_heat_map", "get_spatial_record",
+			},
+		},
+		{
+			name:     "exclude resources",
+			exclude:  []string{"resource:Hotspot*", "resource:HeatMap", "resource:SpatialRecord"},
+			expected: []string{"list_network", "delete_network", "list_firewall_rule"},
+		},
+		{
+			name:     "include resources",
+			include:  []string{"resource:firewall*", "resource:network"},
+			expected: []string{"list_network", "delete_network", "list_firewall_rule"},
+		},
+		{
+			name:     "include names, exclude category",
+			include:  []string{"*_network"},
+			exclude:  []string{"category:delete"},
+			expected: []string{"list_network"},
+		},
+		{
+			name:     "explicit name field is case-insensitive",
+			include:  []string{"NAME:LIST_*"},
+			exclude:  []string{"list_h*"},
+			expected: []string{"list_network", "list_firewall_rule"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filteredNames(t, tt.include, tt.exclude))
+		})
+	}
+}
+
+func TestNewToolFilter_InvalidPatterns(t *testing.T) {
+	_, err := NewToolFilter([]string{"owner:bob"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid include pattern")
+	assert.Contains(t, err.Error(), "unknown field")
+
+	_, err = NewToolFilter(nil, []string{"list_["})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid exclude pattern")
+}
+
+func TestToolsetFilter(t *testing.T) {
+	f, err := NewToolFilter(nil, []string{"category:delete"})
+	require.NoError(t, err)
+
+	filtered := DefaultToolset().Filter(f)
+	require.NotEmpty(t, filtered.Tools)
+	for _, meta := range filtered.Tools {
+		assert.NotEqual(t, "delete", meta.Category)
+	}
+	assert.Len(t, filtered.Handlers, len(filtered.Tools))
+	assert.NotContains(t, filtered.Handlers, "delete_network")
+	assert.Contains(t, filtered.Handlers, "list_network")
+}
+
+func TestToolPatternsMatch(t *testing.T) {
+	patterns, err := ParseToolPatterns([]string{"category:delete", "list_heat_*"})
+	require.NoError(t, err)
+
+	var names []string
+	for _, meta := range filterTestTools {
+		if patterns.Match(meta) {
+			names = append(names, meta.Name)
+		}
+	}
+	assert.Equal(t, []string{"delete_network", "list_heat_map"}, names)
+
+	// No patterns match nothing, unlike an empty include list.
+	assert.False(t, ToolPatterns(nil).Match(filterTestTools[0]))
+}
@@ -21,19 +21,28 @@ var defaultValidator ValidatorFunc = generated.ValidateClientMethods
 // It builds tools dynamically from the metadata and maps each to its
 // corresponding handler from the handler registry.
 func RegisterAllTools(s *server.MCPServer, client unifi.Client) error {
-	return registerAllToolsWithValidator(s, client, defaultValidator)
+	return RegisterToolset(s, client, DefaultToolset())
+}
+
+// RegisterToolset registers every tool in the toolset with the server.
+func RegisterToolset(s *server.MCPServer, client unifi.Client, tools Toolset) error {
+	return registerToolsetWithValidator(s, client, tools, defaultValidator)
 }
 
 // registerAllToolsWithValidator is the internal implementation that allows testing with custom validators.
 func registerAllToolsWithValidator(s *server.MCPServer, client unifi.Client, validator ValidatorFunc) error {
+	return registerToolsetWithValidator(s, client, DefaultToolset(), validator)
+}
+
+func registerToolsetWithValidator(s *server.MCPServer, client unifi.Client, tools Toolset, validator ValidatorFunc) error {
 	// Validate all client methods exist with correct signatures before registration.
 	// Skip validation for nil client (used only in tests).
 	if client != nil {
-		if err := validator(client, generated.AllToolMetadata, generated.TypeRegistry); err != nil {
+		if err := validator(client, tools.Tools, generated.TypeRegistry); err != nil {
 			return fmt.Errorf("client validation failed: %w", err)
 		}
 	}
-	return registerTools(s, client, generated.AllToolMetadata, generated.GetHandlerRegistry())
+	return registerTools(s, client, tools.Tools, tools.Handlers)
 }
 
 // registerTools is the internal implementation that allows testing with custom metadata.
@@ -0,0 +1,175 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Middleware wraps the handler of a single tool with cross-cutting behaviour
+// such as authorization. It receives the client the handler was built for and
+// the tool's metadata so it can decide based on category and resource.
+type Middleware func(client unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc
+
+// Toolset pairs the tools a server exposes with the handlers that implement
+// them. Both eager registration and the lazy-mode meta-tools dispatch through
+// a Toolset, so wrapping it once applies to every mode.
+type Toolset struct {
+	Tools    []generated.ToolMetadata
+	Handlers map[string]generated.HandlerFunc
+
+	// Controllers names the controllers handlers accept in their controller
+	// argument. It is empty when the server talks to a single controller.
+	Controllers []string
+}
+
+// DefaultToolset returns every generated tool with its generated handler.
+func DefaultToolset() Toolset {
+	return Toolset{
+		Tools:    generated.AllToolMetadata,
+		Handlers: generated.GetHandlerRegistry(),
+	}
+}
+
+// Wrap returns a copy of the toolset whose handlers are wrapped by mws.
+// The first middleware is the outermost. Handlers without metadata are
+// left untouched.
+func (t Toolset) Wrap(mws ...Middleware) Toolset {
+	handlers := make(map[string]generated.HandlerFunc, len(t.Handlers))
+	for name, factory := range t.Handlers {
+		handlers[name] = factory
+	}
+
+	for _, meta := range t.Tools {
+		factory, ok := handlers[meta.Name]
+		if !ok {
+			continue
+		}
+		handlers[meta.Name] = wrapFactory(meta, factory, mws)
+	}
+
+	return Toolset{Tools: t.Tools, Handlers: handlers, Controllers: t.Controllers}
+}
+
+func wrapFactory(meta generated.ToolMetadata, factory generated.HandlerFunc, mws []Middleware) generated.HandlerFunc {
+	return func(client unifi.Client) server.ToolHandlerFunc {
+		handler := factory(client)
+		for i := len(mws) - 1; i >= 0; i-- {
+			handler = mws[i](client, meta, handler)
+		}
+		return handler
+	}
+}
+
+// WithArg returns a copy of the toolset in which the tools matched by match
+// declare an extra argument with the given JSON schema. Handlers are left
+// untouched; a middleware is expected to consume the argument.
+func (t Toolset) WithArg(name string, schema map[string]any, match func(generated.ToolMetadata) bool) Toolset {
+	tools := make([]generated.ToolMetadata, len(t.Tools))
+	for i, meta := range t.Tools {
+		if match(meta) {
+			meta.InputSchema = AddProperty(meta.InputSchema, name, schema)
+		}
+		tools[i] = meta
+	}
+	return Toolset{Tools: tools, Handlers: t.Handlers, Controllers: t.Controllers}
+}
+
+// AddProperty returns a copy of an object schema with the named property
+// added.
+func AddProperty(schema map[string]any, name string, prop map[string]any) map[string]any {
+	out := make(map[string]any, len(schema)+1)
+	for k, v := range schema {
+		out[k] = v
+	}
+	props, _ := schema["properties"].(map[string]any)
+	newProps := make(map[string]any, len(props)+1)
+	for k, v := range props {
+		newProps[k] = v
+	}
+	newProps[name] = prop
+	out["properties"] = newProps
+	if out["type"] == nil {
+		out["type"] = "object"
+	}
+	return out
+}
+
+// Deadline returns a Middleware that gives every call d to complete. The
+// deadline applies to the context the handler passes to the controller
+// client, so a slow controller fails the call instead of hanging it.
+func Deadline(d time.Duration) Middleware {
+	return func(_ unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			callCtx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			result, err := next(callCtx, req)
+			if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) && (err != nil || result == nil || result.IsError) {
+				return mcp.NewToolResultError(fmt.Sprintf("tool %s timed out after %s", meta.Name, d)), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// IsReadOnly reports whether a tool only reads from the controller. Anything
+// other than list and get is treated as mutating, so new categories are
+// blocked by read-only mode until explicitly allowed.
+func IsReadOnly(meta generated.ToolMetadata) bool {
+	return meta.Category == "list" || meta.Category == "get"
+}
+
+// ReadOnly returns a toolset that exposes only read-only tools. Mutating
+// handlers stay dispatchable but refuse every call, so execute and batch can
+// explain why a tool is unavailable instead of reporting it as unknown.
+func (t Toolset) ReadOnly() Toolset {
+	tools := make([]generated.ToolMetadata, 0, len(t.Tools))
+	handlers := make(map[string]generated.HandlerFunc, len(t.Handlers))
+	for name, factory := range t.Handlers {
+		handlers[name] = factory
+	}
+
+	for _, meta := range t.Tools {
+		if IsReadOnly(meta) {
+			tools = append(tools, meta)
+			continue
+		}
+		if _, ok := handlers[meta.Name]; ok {
+			handlers[meta.Name] = readOnlyRefusal(meta)
+		}
+	}
+
+	return Toolset{Tools: tools, Handlers: handlers, Controllers: t.Controllers}
+}
+
+func readOnlyRefusal(meta generated.ToolMetadata) generated.HandlerFunc {
+	return func(_ unifi.Client) server.ToolHandlerFunc {
+		return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("tool " + meta.Name + " is disabled: the server is running in read-only mode and " +
+				meta.Category + " operations are not permitted"), nil
+		}
+	}
+}
+
+// ForceDryRun is a Middleware that turns every mutating call into a dry run,
+// so the server can preview changes without ever applying them.
+func ForceDryRun(_ unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if IsReadOnly(meta) {
+		return next
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := make(map[string]any, len(req.GetArguments())+1)
+		for k, v := range req.GetArguments() {
+			args[k] = v
+		}
+		args[generated.DryRunArg] = true
+		req.Params.Arguments = args
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,212 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/claytono/go-unifi-mcp/internal/tools/generated"
+	"github.com/filipowm/go-unifi/unifi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultToolset(t *testing.T) {
+	tools := DefaultToolset()
+	assert.Len(t, tools.Tools, len(generated.AllToolMetadata))
+	assert.Len(t, tools.Handlers, len(generated.AllToolMetadata))
+}
+
+func TestToolsetWrap(t *testing.T) {
+	var calls []string
+	handlerFor := func(name string) generated.HandlerFunc {
+		return func(_ unifi.Client) server.ToolHandlerFunc {
+			return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls = append(calls, name)
+				return mcp.NewToolResultText(name), nil
+			}
+		}
+	}
+	record := func(label string) Middleware {
+		return func(_ unifi.Client, meta generated.ToolMetadata, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls = append(calls, label+":"+meta.Name)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	original := Toolset{
+		Tools: []generated.ToolMetadata{{Name: "list_test", Category: "list", Resource: "Test"}},
+		Handlers: map[string]generated.HandlerFunc{
+			"list_test":  handlerFor("list_test"),
+			"extra_tool": handlerFor("extra_tool"),
+		},
+	}
+
+	wrapped := original.Wrap(record("outer"), record("inner"))
+	require.Len(t, wrapped.Handlers, 2)
+
+	_, err := wrapped.Handlers["list_test"](nil)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer:list_test", "inner:list_test", "list_test"}, calls)
+
+	// Handlers without metadata are passed through unchanged.
+	calls = nil
+	_, err = wrapped.Handlers["extra_tool"](nil)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"extra_tool"}, calls)
+
+	// The original toolset is not modified.
+	calls = nil
+	_, err = original.Handlers["list_test"](nil)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"list_test"}, calls)
+}
+
+func TestIsReadOnly(t *testing.T) {
+	for _, category := range []string{"list", "get"} {
+		assert.True(t, IsReadOnly(generated.ToolMetadata{Category: category}), category)
+	}
+	for _, category := range []string{"create", "update", "delete", "reboot"} {
+		assert.False(t, IsReadOnly(generated.ToolMetadata{Category: category}), category)
+	}
+}
+
+func TestToolsetReadOnly(t *testing.T) {
+	called := false
+	handler := func(_ unifi.Client) server.ToolHandlerFunc {
+		return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			called = true
+			return mcp.NewToolResultText("ok"), nil
+		}
+	}
+
+	original := Toolset{
+		Tools: []generated.ToolMetadata{
+			{Name: "list_test", Category: "list", Resource: "Test"},
+			{Name: "get_test", Category: "get", Resource: "Test"},
+			{Name: "delete_test", Category: "delete", Resource: "Test"},
+		},
+		Handlers: map[string]generated.HandlerFunc{
+			"list_test":   handler,
+			"get_test":    handler,
+			"delete_test": handler,
+		},
+	}
+
+	readOnly := original.ReadOnly()
+	require.Len(t, readOnly.Tools, 2)
+	assert.Equal(t, "list_test", readOnly.Tools[0].Name)
+	assert.Equal(t, "get_test", readOnly.Tools[1].Name)
+
+	// Mutating handlers remain dispatchable but refuse with an explanation.
+	require.Contains(t, readOnly.Handlers, "delete_test")
+	result, err := readOnly.Handlers["delete_test"](nil)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "read-only mode")
+	assert.False(t, called)
+
+	result, err = readOnly.Handlers["list_test"](nil)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.True(t, called)
+
+	// The original toolset is not modified.
+	assert.Len(t, original.Tools, 3)
+}
+
+func TestRegisterToolset_ReadOnly(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+
+	err := RegisterToolset(s, nil, DefaultToolset().ReadOnly())
+	require.NoError(t, err)
+
+	for name := range s.ListTools() {
+		assert.Regexp(t, `^(list|get)_`, name)
+	}
+	assert.NotEmpty(t, s.ListTools())
+}
+
+func TestForceDryRun(t *testing.T) {
+	var seen map[string]any
+	next := func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = req.GetArguments()
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	args := map[string]any{"id": "123", "dry_run": false}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+
+	handler := ForceDryRun(nil, generated.ToolMetadata{Name: "delete_network", Category: "delete"}, next)
+	_, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "123", "dry_run": true}, seen)
+	assert.Equal(t, false, args["dry_run"], "caller arguments must not be modified")
+
+	handler = ForceDryRun(nil, generated.ToolMetadata{Name: "list_network", Category: "list"}, next)
+	_, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, false, seen["dry_run"])
+}
+
+func TestDeadline(t *testing.T) {
+	meta := generated.ToolMetadata{Name: "list_network", Category: "list"}
+	var deadline time.Time
+	next := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deadline, _ = ctx.Deadline()
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	result, err := Deadline(time.Minute)(nil, meta, next)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+
+	slow := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return mcp.NewToolResultError(ctx.Err().Error()), nil
+	}
+	result, err = Deadline(10*time.Millisecond)(nil, meta, slow)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Equal(t, "tool list_network timed out after 10ms", result.Content[0].(mcp.TextContent).Text)
+
+	// A call canceled by the caller is not reported as timed out.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = Deadline(time.Minute)(nil, meta, slow)(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "context canceled", result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestToolsetWithArg(t *testing.T) {
+	tools := Toolset{Tools: []generated.ToolMetadata{
+		{Name: "list_network", Category: "list", InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"site": map[string]any{"type": "string"}},
+		}},
+		{Name: "delete_network", Category: "delete"},
+	}}
+	schema := map[string]any{"type": "boolean"}
+
+	got := tools.WithArg("no_cache", schema, IsReadOnly)
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"site":     map[string]any{"type": "string"},
+			"no_cache": schema,
+		},
+	}, got.Tools[0].InputSchema)
+	assert.Nil(t, got.Tools[1].InputSchema)
+	assert.NotContains(t, tools.Tools[0].InputSchema["properties"], "no_cache", "original schema must not be modified")
+
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"no_cache": schema},
+	}, AddProperty(nil, "no_cache", schema))
+}